@@ -2,9 +2,11 @@ package openweathermap
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 	"github.com/Vivek-Prakash1307/weather-Microservices/internal/models"
 )
@@ -16,30 +18,64 @@ type Client struct {
 	baseURL    string
 }
 
+// NotFoundError is returned when OpenWeatherMap does not know the city
+type NotFoundError struct {
+	City string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("city '%s' not found", e.City)
+}
+
+// DefaultBaseURL is the public OpenWeatherMap API
+const DefaultBaseURL = "https://api.openweathermap.org/data/2.5"
+
 // NewClient creates a new OpenWeatherMap API client
 func NewClient(apiKey string) *Client {
+	return NewClientWithBaseURL(apiKey, DefaultBaseURL)
+}
+
+// NewClientWithBaseURL creates a client for an OpenWeatherMap-compatible API
+// at baseURL, such as a proxy or a test server
+func NewClientWithBaseURL(apiKey, baseURL string) *Client {
 	return &Client{
 		apiKey: apiKey,
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
-		baseURL: "https://api.openweathermap.org/data/2.5",
+		baseURL: strings.TrimRight(baseURL, "/"),
 	}
 }
 
-// GetWeather fetches weather data for a city
-func (c *Client) GetWeather(city string) (*models.OpenWeatherResponse, error) {
+// BaseURL returns the API root the client talks to
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// get performs a GET request. Transport errors quote the request URL, so the
+// API key is redacted before the error reaches logs or responses.
+func (c *Client) get(requestURL string) (*http.Response, error) {
+	resp, err := c.httpClient.Get(requestURL)
+	if err != nil && c.apiKey != "" {
+		err = errors.New(strings.ReplaceAll(err.Error(), c.apiKey, "[REDACTED]"))
+	}
+	return resp, err
+}
+
+// GetWeather fetches weather data for a city in the units and language
+// selected by opts
+func (c *Client) GetWeather(city string, opts Options) (*models.OpenWeatherResponse, error) {
 	encodedCity := url.QueryEscape(city)
-	url := fmt.Sprintf("%s/weather?q=%s&appid=%s&units=metric", c.baseURL, encodedCity, c.apiKey)
+	url := fmt.Sprintf("%s/weather?q=%s&appid=%s%s", c.baseURL, encodedCity, c.apiKey, opts.query())
 
-	resp, err := c.httpClient.Get(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch weather data: %v", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("city '%s' not found", city)
+		return nil, &NotFoundError{City: city}
 	} else if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("weather API returned status: %s", resp.Status)
 	}
@@ -52,11 +88,34 @@ func (c *Client) GetWeather(city string) (*models.OpenWeatherResponse, error) {
 	return &weatherResponse, nil
 }
 
+// GetWeatherByCoords fetches weather data for coordinates in the units and
+// language selected by opts
+func (c *Client) GetWeatherByCoords(lat, lon float64, opts Options) (*models.OpenWeatherResponse, error) {
+	url := fmt.Sprintf("%s/weather?lat=%f&lon=%f&appid=%s%s", c.baseURL, lat, lon, c.apiKey, opts.query())
+
+	resp, err := c.get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch weather data: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("weather API returned status: %s", resp.Status)
+	}
+
+	var weatherResponse models.OpenWeatherResponse
+	if err := json.NewDecoder(resp.Body).Decode(&weatherResponse); err != nil {
+		return nil, fmt.Errorf("failed to parse weather data: %v", err)
+	}
+
+	return &weatherResponse, nil
+}
+
 // GetUVIndex fetches UV index for coordinates
 func (c *Client) GetUVIndex(lat, lon float64) (float64, error) {
 	url := fmt.Sprintf("%s/uvi?lat=%f&lon=%f&appid=%s", c.baseURL, lat, lon, c.apiKey)
 
-	resp, err := c.httpClient.Get(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return 0, err
 	}
@@ -78,7 +137,7 @@ func (c *Client) GetUVIndex(lat, lon float64) (float64, error) {
 func (c *Client) GetAirQuality(lat, lon float64) (int, string, error) {
 	url := fmt.Sprintf("%s/air_pollution?lat=%f&lon=%f&appid=%s", c.baseURL, lat, lon, c.apiKey)
 
-	resp, err := c.httpClient.Get(url)
+	resp, err := c.get(url)
 	if err != nil {
 		return 0, "", err
 	}
@@ -0,0 +1,84 @@
+package openweathermap
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Units is an OpenWeatherMap unit system. It sets the scale of temperatures
+// and wind speed in upstream responses.
+type Units string
+
+const (
+	// Metric reports degrees Celsius and metres per second
+	Metric Units = "metric"
+	// Imperial reports degrees Fahrenheit and miles per hour
+	Imperial Units = "imperial"
+	// Standard reports kelvin and metres per second
+	Standard Units = "standard"
+)
+
+// Options selects the unit system and language of a weather lookup. The zero
+// value asks for metric units with English condition descriptions.
+type Options struct {
+	Units Units
+	Lang  string
+}
+
+// ParseUnits validates a unit system name. An empty name means Metric.
+func ParseUnits(name string) (Units, error) {
+	switch units := Units(strings.ToLower(name)); units {
+	case "":
+		return Metric, nil
+	case Metric, Imperial, Standard:
+		return units, nil
+	}
+	return "", fmt.Errorf("unknown units '%s'. Supported: metric, imperial, standard", name)
+}
+
+// languages maps the accepted lang values to the code OpenWeatherMap expects.
+// ISO 639-1 codes are accepted alongside the upstream spellings, so "cs" and
+// "cz" both select Czech.
+var languages = map[string]string{
+	"af": "af", "al": "al", "sq": "al", "ar": "ar", "az": "az", "bg": "bg",
+	"ca": "ca", "cz": "cz", "cs": "cz", "da": "da", "de": "de", "el": "el",
+	"en": "en", "eu": "eu", "fa": "fa", "fi": "fi", "fr": "fr", "gl": "gl",
+	"he": "he", "hi": "hi", "hr": "hr", "hu": "hu", "id": "id", "it": "it",
+	"ja": "ja", "kr": "kr", "ko": "kr", "la": "la", "lv": "la", "lt": "lt",
+	"mk": "mk", "no": "no", "nb": "no", "nn": "no", "nl": "nl", "pl": "pl",
+	"pt": "pt", "pt_br": "pt_br", "ro": "ro", "ru": "ru", "sv": "sv", "se": "sv",
+	"sk": "sk", "sl": "sl", "es": "es", "sp": "es", "sr": "sr", "th": "th",
+	"tr": "tr", "uk": "uk", "ua": "uk", "vi": "vi", "zh_cn": "zh_cn",
+	"zh_tw": "zh_tw", "zu": "zu",
+}
+
+// ParseLang validates a language code and returns the code OpenWeatherMap
+// expects. Codes are case-insensitive and may use a hyphen, as in pt-BR.
+// English is the upstream default, so both "" and "en" return "".
+func ParseLang(code string) (string, error) {
+	if code == "" {
+		return "", nil
+	}
+	lang, known := languages[strings.ReplaceAll(strings.ToLower(code), "-", "_")]
+	if !known {
+		return "", fmt.Errorf("unsupported lang '%s'. Use a language code such as de, fr or pt_br", code)
+	}
+	if lang == "en" {
+		return "", nil
+	}
+	return lang, nil
+}
+
+// query returns the units and lang query parameters for an upstream request
+func (o Options) query() string {
+	units := o.Units
+	if units == "" {
+		units = Metric
+	}
+	query := "&units=" + string(units)
+	if o.Lang != "" {
+		query += "&lang=" + url.QueryEscape(o.Lang)
+	}
+	return query
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/api/openweathermap"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/cache"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/config"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/logging"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/metrics"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/models"
+)
+
+// runInitConfig writes an example config file
+func runInitConfig(args []string) int {
+	flags := flag.NewFlagSet("init-config", flag.ExitOnError)
+	configPath := flags.String("config", defaultConfigPath, "Path of the config file to write")
+	force := flags.Bool("force", false, "Overwrite an existing file")
+	flags.Parse(args)
+
+	if _, err := os.Stat(*configPath); err == nil && !*force {
+		fmt.Fprintf(os.Stderr, "❌ %s already exists; use -force to overwrite it\n", *configPath)
+		return 1
+	}
+	if err := config.SaveExampleConfig(*configPath); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	fmt.Printf("✅ Wrote example config to %s; set OpenWeatherMapApiKey before starting the server\n", *configPath)
+	return 0
+}
+
+// runValidateConfig loads the config file and environment and reports every problem
+func runValidateConfig(args []string) int {
+	flags := flag.NewFlagSet("validate-config", flag.ExitOnError)
+	configPath := flags.String("config", defaultConfigPath, "Path to config file")
+	flags.Parse(args)
+
+	_, err := config.LoadConfig(*configPath)
+	return reportValidation(*configPath, err)
+}
+
+// reportValidation prints the result of loading the config and returns the
+// process exit code
+func reportValidation(configPath string, err error) int {
+	var invalid *config.ValidationError
+	switch {
+	case errors.As(err, &invalid):
+		fmt.Fprintf(os.Stderr, "❌ %s has %d problem(s):\n", configPath, len(invalid.Problems))
+		for _, problem := range invalid.Problems {
+			fmt.Fprintf(os.Stderr, "   - %s\n", problem)
+		}
+		return 1
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	fmt.Printf("✅ %s is valid\n", configPath)
+	return 0
+}
+
+// runFetch looks up the weather for one location through the configured
+// cache and prints it
+func runFetch(args []string) int {
+	flags := flag.NewFlagSet("fetch", flag.ExitOnError)
+	configPath := flags.String("config", defaultConfigPath, "Path to config file")
+	city := flags.String("city", "", "City name, e.g. London")
+	lat := flags.Float64("lat", 0, "Latitude, used with -lon")
+	lon := flags.Float64("lon", 0, "Longitude, used with -lat")
+	units := flags.String("units", "metric", "Upstream unit system: metric, imperial or standard")
+	lang := flags.String("lang", "", "Language of condition descriptions, e.g. de")
+	format := flags.String("format", "table", "Output format: table or json")
+	flags.Parse(args)
+
+	byCoords := isFlagSet(flags, "lat") || isFlagSet(flags, "lon")
+	if (*city == "") == !byCoords || !validFormat(*format) {
+		fmt.Fprintln(os.Stderr, "Usage: weather-server fetch (-city NAME | -lat LAT -lon LON) [-units UNITS] [-lang LANG] [-format table|json]")
+		return 2
+	}
+	var opts openweathermap.Options
+	var err error
+	if opts.Units, err = openweathermap.ParseUnits(*units); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 2
+	}
+	if opts.Lang, err = openweathermap.ParseLang(*lang); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 2
+	}
+
+	cfg, ok := loadConfigForCommand(*configPath)
+	if !ok {
+		return 1
+	}
+	cacheManager, err := newCacheManager(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to initialize cache: %v\n", err)
+		return 1
+	}
+	defer cacheManager.Close()
+	weatherService, err := newWeatherService(cfg, cacheManager, metrics.NewMetricsManager())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Invalid location cache settings: %v\n", err)
+		return 1
+	}
+
+	var data *models.WeatherData
+	if byCoords {
+		data, err = weatherService.GetWeatherDataByCoordsForTenant(nil, *lat, *lon, opts)
+	} else {
+		data, err = weatherService.GetWeatherDataForTenant(nil, *city, opts)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+
+	if *format == "json" {
+		return printJSON(data)
+	}
+	printWeatherTable(os.Stdout, data)
+	return 0
+}
+
+// runCache runs a cache subcommand. Only "dump" is supported.
+func runCache(args []string) int {
+	if len(args) == 0 || args[0] != "dump" {
+		fmt.Fprintln(os.Stderr, "Usage: weather-server cache dump [-config FILE] [-format table|json]")
+		return 2
+	}
+
+	flags := flag.NewFlagSet("cache dump", flag.ExitOnError)
+	configPath := flags.String("config", defaultConfigPath, "Path to config file")
+	format := flags.String("format", "table", "Output format: table or json")
+	flags.Parse(args[1:])
+	if !validFormat(*format) {
+		fmt.Fprintln(os.Stderr, "Usage: weather-server cache dump [-config FILE] [-format table|json]")
+		return 2
+	}
+
+	cfg, ok := loadConfigForCommand(*configPath)
+	if !ok {
+		return 1
+	}
+
+	// A memory cache lives inside the server process, so read its snapshot instead
+	var cacheManager *cache.CacheManager
+	if cfg.CacheBackend == "redis" {
+		var err error
+		if cacheManager, err = newCacheManager(cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Failed to initialize cache: %v\n", err)
+			return 1
+		}
+	} else {
+		if cfg.CacheSnapshotFile == "" {
+			fmt.Fprintln(os.Stderr, "❌ The memory cache is only visible inside the server; set CacheSnapshotFile or use GET /cache")
+			return 1
+		}
+		cacheManager = cache.NewCacheManager(time.Duration(cfg.CacheExpiryMinutes) * time.Minute)
+		if _, err := cacheManager.LoadSnapshot(cfg.CacheSnapshotFile); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			return 1
+		}
+	}
+	defer cacheManager.Close()
+
+	keys, err := cacheManager.Keys()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+
+	entries := make([]cacheDumpEntry, 0, len(keys))
+	for _, key := range keys {
+		if entry, found := cacheManager.Peek(key); found {
+			entries = append(entries, cacheDumpEntry{Key: key, StoredAt: entry.StoredAt, ExpiresAt: entry.ExpiresAt, Data: entry.Data})
+		}
+	}
+
+	if *format == "json" {
+		return printJSON(entries)
+	}
+	printCacheTable(os.Stdout, entries)
+	return 0
+}
+
+// cacheDumpEntry is one entry printed by "cache dump"
+type cacheDumpEntry struct {
+	Key       string             `json:"key"`
+	StoredAt  time.Time          `json:"stored_at"`
+	ExpiresAt time.Time          `json:"expires_at"`
+	Data      models.WeatherData `json:"data"`
+}
+
+// loadConfigForCommand loads the config for a one-shot command, printing
+// problems instead of logging them
+func loadConfigForCommand(configPath string) (*config.Config, bool) {
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		reportValidation(configPath, err)
+		return nil, false
+	}
+	level, _ := logging.ParseLevel(cfg.LogLevel)
+	logging.SetLevel(level)
+	return cfg, true
+}
+
+func isFlagSet(flags *flag.FlagSet, name string) bool {
+	set := false
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+func validFormat(format string) bool {
+	return format == "table" || format == "json"
+}
+
+func printJSON(value interface{}) int {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func printWeatherTable(w io.Writer, data *models.WeatherData) {
+	conditions := make([]string, 0, len(data.Weather))
+	for _, weather := range data.Weather {
+		conditions = append(conditions, fmt.Sprintf("%s (%s)", weather.Main, weather.Description))
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(table, "Location\t%s, %s\n", data.Name, data.Country)
+	fmt.Fprintf(table, "Coordinates\t%.4f, %.4f\n", data.Coordinates.Latitude, data.Coordinates.Longitude)
+	fmt.Fprintf(table, "Local time\t%s\n", data.LocalTime)
+	fmt.Fprintf(table, "Conditions\t%s\n", strings.Join(conditions, ", "))
+	fmt.Fprintf(table, "Temperature\t%.1f°C / %.1f°F\n", data.Main.Celsius, data.Main.Fahrenheit)
+	fmt.Fprintf(table, "Feels like\t%.1f°C / %.1f°F\n", data.Main.FeelsLike.Celsius, data.Main.FeelsLike.Fahrenheit)
+	fmt.Fprintf(table, "Min / max\t%.1f°C / %.1f°C\n", data.Main.MinTemp.Celsius, data.Main.MaxTemp.Celsius)
+	fmt.Fprintf(table, "Humidity\t%d%%\n", data.Main.Humidity)
+	fmt.Fprintf(table, "Pressure\t%d hPa\n", data.Main.Pressure)
+	fmt.Fprintf(table, "Wind\t%.1f m/s %s\n", data.Wind.Speed, data.Wind.Direction)
+	fmt.Fprintf(table, "Cloudiness\t%d%%\n", data.Clouds.Cloudiness)
+	fmt.Fprintf(table, "Visibility\t%d m\n", data.Visibility)
+	fmt.Fprintf(table, "UV index\t%.1f\n", data.UVIndex)
+	fmt.Fprintf(table, "Air quality\t%s (AQI %d)\n", data.AirQuality, data.AQI)
+	fmt.Fprintf(table, "Sunrise / sunset\t%s / %s\n", data.SunriseTime, data.SunsetTime)
+	fmt.Fprintf(table, "Cached\t%v\n", data.CacheHit)
+	table.Flush()
+}
+
+func printCacheTable(w io.Writer, entries []cacheDumpEntry) {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "KEY\tLOCATION\tTEMP\tAGE\tEXPIRES IN")
+	for _, entry := range entries {
+		location := "-"
+		if entry.Data.Name != "" {
+			location = entry.Data.Name + ", " + entry.Data.Country
+		}
+		// Entries restored from an older snapshot do not know their age
+		age := "-"
+		if !entry.StoredAt.IsZero() {
+			age = time.Since(entry.StoredAt).Round(time.Second).String()
+		}
+		fmt.Fprintf(table, "%s\t%s\t%.1f°C\t%s\t%s\n",
+			entry.Key,
+			location,
+			entry.Data.Main.Celsius,
+			age,
+			time.Until(entry.ExpiresAt).Round(time.Second),
+		)
+	}
+	table.Flush()
+	fmt.Fprintf(w, "%d entries\n", len(entries))
+}
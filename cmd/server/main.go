@@ -1,102 +1,67 @@
 package main
 
 import (
-	"context"
-	"flag"
-	"log"
-	"net/http"
+	"fmt"
+	"io"
 	"os"
-	"os/signal"
-	"syscall"
-	"time"
-
-	"github.com/Vivek-Prakash1307/weather-Microservices/internal/config"
-	"github.com/Vivek-Prakash1307/weather-Microservices/internal/handlers"
-	"github.com/Vivek-Prakash1307/weather-Microservices/internal/middleware"
-	"github.com/Vivek-Prakash1307/weather-Microservices/internal/services"
-	"github.com/Vivek-Prakash1307/weather-Microservices/api/openweathermap"
-	"github.com/Vivek-Prakash1307/weather-Microservices/internal/cache"
-	"github.com/Vivek-Prakash1307/weather-Microservices/internal/metrics"
-
-	"github.com/gorilla/mux"
+	"strings"
 )
 
-func main() {
-	// Parse command-line flags
-	configPath := flag.String("config", ".apiConfig", "Path to config file")
-	port := flag.String("port", "8080", "Server port")
-	flag.Parse()
+// defaultConfigPath is the config file every command reads unless -config is given
+const defaultConfigPath = ".apiConfig"
+
+// command is a subcommand of the weather-server binary
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(args []string) int
+}
+
+var commands = []command{
+	{"serve", "serve [-config FILE] [-port PORT]", "Run the HTTP server (default)", runServe},
+	{"init-config", "init-config [-config FILE] [-force]", "Write an example config file", runInitConfig},
+	{"validate-config", "validate-config [-config FILE]", "Check a config file and exit non-zero if it is invalid", runValidateConfig},
+	{"fetch", "fetch (-city NAME | -lat LAT -lon LON) [-units UNITS] [-lang LANG] [-format table|json]", "Look up the weather once and print it", runFetch},
+	{"cache", "cache dump [-config FILE] [-format table|json]", "Print the entries in the configured cache", runCache},
+}
 
-	// Override port with environment variable if set
-	if envPort := os.Getenv("PORT"); envPort != "" {
-		*port = envPort
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run dispatches to a subcommand. Running without one, or with only flags,
+// starts the server so existing deployments keep working.
+func run(args []string) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") && !isHelp(args[0]) {
+		return runServe(args)
 	}
-
-	// Load configuration
-	cfg, err := config.LoadConfig(*configPath)
-	if err != nil {
-		log.Fatalf("❌ Failed to load config: %v", err)
+	if isHelp(args[0]) || args[0] == "help" {
+		printUsage(os.Stdout)
+		return 0
 	}
-	log.Println("✅ Configuration loaded successfully")
-
-	// Initialize components
-	cacheManager := cache.NewCacheManager(time.Duration(cfg.CacheExpiryMinutes) * time.Minute)
-	metricsManager := metrics.NewMetricsManager()
-	weatherClient := openweathermap.NewClient(cfg.OpenWeatherMapApiKey)
-	weatherService := services.NewWeatherService(weatherClient, cacheManager, metricsManager)
-	handler := handlers.NewHandler(weatherService, metricsManager, cacheManager)
-
-	// Setup router with middleware
-	router := mux.NewRouter()
-	router.Use(middleware.LoggingMiddleware)
-	router.Use(middleware.CORSMiddleware)
-	router.Use(middleware.RecoveryMiddleware)
-	router.Use(middleware.RateLimitMiddleware(cfg.RateLimitPerMinute))
-
-	// Register routes
-	router.HandleFunc("/", handler.RootHandler).Methods("GET")
-	router.HandleFunc("/health", handler.HealthHandler).Methods("GET")
-	router.HandleFunc("/readiness", handler.ReadinessHandler).Methods("GET")
-	router.HandleFunc("/weather", handler.WeatherHandler).Methods("GET")
-	router.HandleFunc("/metrics", handler.MetricsHandler).Methods("GET")
-	router.HandleFunc("/cache", handler.CacheHandler).Methods("GET")
-	router.HandleFunc("/cache/clear", handler.CacheClearHandler).Methods("POST")
-
-	// Create HTTP server with timeouts
-	srv := &http.Server{
-		Addr:         ":" + *port,
-		Handler:      router,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
-	}
-
-	// Start server in goroutine
-	go func() {
-		log.Printf("🌍 Weather Microservice starting on port %s", *port)
-		log.Printf("📖 API Documentation: http://localhost:%s", *port)
-		log.Printf("🌤️  Weather endpoint: http://localhost:%s/weather?city=London", *port)
-		log.Printf("❤️  Health check: http://localhost:%s/health", *port)
-		log.Printf("📊 Metrics: http://localhost:%s/metrics", *port)
-		log.Printf("💾 Cache status: http://localhost:%s/cache", *port)
-
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("❌ Failed to start server: %v", err)
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(args[1:])
 		}
-	}()
-
-	// Graceful shutdown
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-
-	log.Println("🛑 Shutting down server gracefully...")
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatalf("❌ Server forced to shutdown: %v", err)
 	}
-
-	log.Println("✅ Server exited gracefully")
-}
\ No newline at end of file
+	fmt.Fprintf(os.Stderr, "unknown command '%s'\n\n", args[0])
+	printUsage(os.Stderr)
+	return 2
+}
+
+func isHelp(arg string) bool {
+	return arg == "-h" || arg == "-help" || arg == "--help"
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: weather-server <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-18s %s\n", cmd.name, cmd.summary)
+		fmt.Fprintf(w, "  %-18s   weather-server %s\n", "", cmd.usage)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'weather-server <command> -h' for the flags of a command.")
+}
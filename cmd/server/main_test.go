@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/cache"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/models"
+)
+
+// newFakeUpstream serves canned OpenWeatherMap responses for London
+func newFakeUpstream(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/weather":
+			w.Write([]byte(`{"name":"London","coord":{"lat":51.5085,"lon":-0.1257},"main":{"temp":15},"wind":{"speed":5},"weather":[{"main":"Clouds","description":"overcast clouds"}],"sys":{"country":"GB"}}`))
+		case "/uvi":
+			w.Write([]byte(`{"value":4.5}`))
+		case "/air_pollution":
+			w.Write([]byte(`{"list":[{"main":{"aqi":2}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// writeConfig writes a config file into a temporary directory
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return filename
+}
+
+// capture runs fn with stdout and stderr redirected and returns its exit code
+// and output
+func capture(t *testing.T, fn func() int) (code int, stdout, stderr string) {
+	t.Helper()
+	read := func(target **os.File) func() string {
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatalf("os.Pipe() error = %v", err)
+		}
+		original := *target
+		*target = w
+		output := make(chan string)
+		go func() {
+			bytes, _ := io.ReadAll(r)
+			output <- string(bytes)
+		}()
+		return func() string {
+			*target = original
+			w.Close()
+			return <-output
+		}
+	}
+	restoreStdout := read(&os.Stdout)
+	restoreStderr := read(&os.Stderr)
+	code = fn()
+	return code, restoreStdout(), restoreStderr()
+}
+
+func TestRun_DefaultsToServe(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		expectedCode int
+		output       string
+	}{
+		{"Valid config", `{"OpenWeatherMapApiKey": "key"}`, 0, "is valid"},
+		{"Invalid config", `{"OpenWeatherMapApiKey": "key", "ServerPort": "70000"}`, 1, "ServerPort"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := writeConfig(t, tt.content)
+			code, stdout, stderr := capture(t, func() int {
+				return run([]string{"-config", configPath, "-validate-config"})
+			})
+			if code != tt.expectedCode {
+				t.Errorf("Expected exit code %d, got %d", tt.expectedCode, code)
+			}
+			if !strings.Contains(stdout+stderr, tt.output) {
+				t.Errorf("Expected output mentioning %q, got %q", tt.output, stdout+stderr)
+			}
+		})
+	}
+}
+
+func TestRun_UnknownCommand(t *testing.T) {
+	code, _, stderr := capture(t, func() int { return run([]string{"frobnicate"}) })
+	if code != 2 {
+		t.Errorf("Expected exit code 2, got %d", code)
+	}
+	if !strings.Contains(stderr, "unknown command 'frobnicate'") || !strings.Contains(stderr, "Usage:") {
+		t.Errorf("Expected the error and usage on stderr, got %q", stderr)
+	}
+}
+
+func TestRun_FetchJSON(t *testing.T) {
+	upstream := newFakeUpstream(t)
+	configPath := writeConfig(t, `{"OpenWeatherMapApiKey": "key", "BaseURL": "`+upstream.URL+`", "LogLevel": "error"}`)
+
+	code, stdout, stderr := capture(t, func() int {
+		return run([]string{"fetch", "-config", configPath, "-city", "London", "-format", "json"})
+	})
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d (%s)", code, stderr)
+	}
+	var data models.WeatherData
+	if err := json.Unmarshal([]byte(stdout), &data); err != nil {
+		t.Fatalf("Expected JSON output, got %q: %v", stdout, err)
+	}
+	if data.Name != "London" || data.Main.Celsius != 15 || data.UVIndex != 4.5 || data.AQI != 2 {
+		t.Errorf("Unexpected weather: %+v", data)
+	}
+}
+
+func TestRun_CacheDumpFromSnapshot(t *testing.T) {
+	snapshotFile := filepath.Join(t.TempDir(), "cache.json")
+	backend := cache.NewMemoryBackend()
+	cacheManager := cache.NewCacheManagerWithBackend(10*time.Minute, backend)
+	storedAt := time.Now().Add(-time.Hour)
+	backend.SetEntry("london", cache.Entry{Data: models.WeatherData{Name: "London"}, StoredAt: storedAt, ExpiresAt: time.Now().Add(time.Hour)}, time.Hour)
+	cacheManager.Set("paris", models.WeatherData{Name: "Paris"})
+	if _, err := cacheManager.SaveSnapshot(snapshotFile); err != nil {
+		t.Fatalf("SaveSnapshot() error = %v", err)
+	}
+	configPath := writeConfig(t, `{"OpenWeatherMapApiKey": "key", "CacheSnapshotFile": "`+snapshotFile+`", "LogLevel": "error"}`)
+
+	code, stdout, stderr := capture(t, func() int {
+		return run([]string{"cache", "dump", "-config", configPath, "-format", "json"})
+	})
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d (%s)", code, stderr)
+	}
+	var entries []cacheDumpEntry
+	if err := json.Unmarshal([]byte(stdout), &entries); err != nil {
+		t.Fatalf("Expected JSON output, got %q: %v", stdout, err)
+	}
+	if len(entries) != 2 || entries[0].Key != "london" || entries[1].Data.Name != "Paris" {
+		t.Fatalf("Expected the snapshot's entries in key order, got %+v", entries)
+	}
+	if !entries[0].StoredAt.Equal(storedAt) {
+		t.Errorf("Expected the original write time %v, got %v", storedAt, entries[0].StoredAt)
+	}
+
+	_, stdout, _ = capture(t, func() int {
+		return run([]string{"cache", "dump", "-config", configPath})
+	})
+	if !strings.Contains(stdout, "1h0m0s") {
+		t.Errorf("Expected london's age in the table, got %q", stdout)
+	}
+
+	code, _, _ = capture(t, func() int { return run([]string{"cache", "list"}) })
+	if code != 2 {
+		t.Errorf("Expected exit code 2 for an unknown cache subcommand, got %d", code)
+	}
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/api/openweathermap"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/auth"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/cache"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/config"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/handlers"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/logging"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/metrics"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/middleware"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/server"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/services"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/tenant"
+
+	"github.com/gorilla/mux"
+)
+
+// runServe starts the HTTP server and blocks until it is shut down
+func runServe(args []string) int {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	configPath := flags.String("config", defaultConfigPath, "Path to config file")
+	port := flags.String("port", "", "Server port (overrides SERVER_PORT, PORT and the config file)")
+	validateOnly := flags.Bool("validate-config", false, "Validate the config file and environment, then exit")
+	flags.Parse(args)
+
+	// Load configuration: flags > environment > config file > defaults
+	cfg, err := config.LoadConfig(*configPath)
+	if *validateOnly {
+		return reportValidation(*configPath, err)
+	}
+	if err != nil {
+		log.Fatalf("❌ Failed to load config: %v", err)
+	}
+	if *port == "" {
+		*port = cfg.ServerPort
+	}
+	log.Println("✅ Configuration loaded successfully")
+	logLevel, _ := logging.ParseLevel(cfg.LogLevel)
+	logging.SetLevel(logLevel)
+
+	// Initialize components
+	cacheManager, err := newCacheManager(cfg)
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize cache: %v", err)
+	}
+
+	// Warm the in-memory cache from the previous run's snapshot
+	snapshotFile := ""
+	if cfg.CacheBackend == "memory" {
+		snapshotFile = cfg.CacheSnapshotFile
+	}
+	if snapshotFile != "" {
+		restored, err := cacheManager.LoadSnapshot(snapshotFile)
+		if err != nil {
+			log.Printf("⚠️  Ignoring cache snapshot: %v", err)
+		} else {
+			log.Printf("♻️  Restored %d cache entries from %s", restored, snapshotFile)
+		}
+	}
+	metricsManager := metrics.NewMetricsManager()
+	weatherService, err := newWeatherService(cfg, cacheManager, metricsManager)
+	if err != nil {
+		log.Fatalf("❌ Invalid location cache settings: %v", err)
+	}
+	handler := handlers.NewHandler(weatherService, metricsManager, cacheManager)
+
+	// Setup authentication
+	var verifier *auth.Verifier
+	if cfg.Auth.Enabled {
+		verifier, err = auth.NewVerifier(auth.Options{
+			JWKSURL:  cfg.Auth.JWKSURL,
+			KeyFile:  cfg.Auth.KeyFile,
+			Issuer:   cfg.Auth.Issuer,
+			Audience: cfg.Auth.Audience,
+		})
+		if err != nil {
+			log.Fatalf("❌ Failed to initialize authentication: %v", err)
+		}
+		log.Println("🔐 JWT authentication enabled")
+	}
+
+	// Setup tenants
+	tenants := tenantsFromConfig(cfg)
+	registry := tenant.NewRegistry(tenants, &tenant.Tenant{ID: tenant.DefaultID})
+	if registry.Enabled() {
+		log.Printf("👥 Multi-tenancy enabled with %d tenants", len(tenants))
+	}
+
+	// Setup router with middleware
+	rateLimiter := middleware.NewRateLimiter(cfg.RateLimitPerMinute)
+	router := server.NewRouter(server.Options{
+		Handler:     handler,
+		Verifier:    verifier,
+		Registry:    registry,
+		RateLimiter: rateLimiter,
+	})
+
+	// CORS wraps the router so preflight requests are answered before route matching
+	corsPolicy := middleware.NewCORSPolicy(corsOptions(cfg, router))
+
+	// Reload settings on SIGHUP or when the config file changes
+	watcher := config.NewWatcher(*configPath, cfg, func(old, updated *config.Config) {
+		cacheManager.SetCacheTime(time.Duration(updated.CacheExpiryMinutes) * time.Minute)
+		cacheManager.SetNegativeCacheTime(time.Duration(updated.NegativeCacheMinutes) * time.Minute)
+		weatherService.SetDataTTLs(time.Duration(updated.UVCacheMinutes)*time.Minute, time.Duration(updated.AirQualityCacheMins)*time.Minute)
+		rateLimiter.SetLimit(updated.RateLimitPerMinute)
+		level, _ := logging.ParseLevel(updated.LogLevel)
+		logging.SetLevel(level)
+		if updated.OpenWeatherMapApiKey != old.OpenWeatherMapApiKey || updated.BaseURL != old.BaseURL {
+			weatherService.SetClient(openweathermap.NewClientWithBaseURL(updated.OpenWeatherMapApiKey, updated.BaseURL))
+		}
+		registry.Replace(tenantsFromConfig(updated), &tenant.Tenant{ID: tenant.DefaultID})
+		corsPolicy.Update(corsOptions(updated, router))
+	})
+	handler.SetConfigSource(watcher.Current)
+	watcher.Start(2 * time.Second)
+
+	// Create HTTP server with timeouts
+	srv := &http.Server{
+		Addr:         ":" + *port,
+		Handler:      corsPolicy.Middleware(router),
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+
+	// Start server in goroutine
+	go func() {
+		log.Printf("🌍 Weather Microservice starting on port %s", *port)
+		log.Printf("📖 API Documentation: http://localhost:%s", *port)
+		log.Printf("🌤️  Weather endpoint: http://localhost:%s/v1/weather?city=London", *port)
+		log.Printf("❤️  Health check: http://localhost:%s/health", *port)
+		log.Printf("📊 Metrics: http://localhost:%s/metrics", *port)
+		log.Printf("💾 Cache status: http://localhost:%s/v1/cache", *port)
+
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("❌ Failed to start server: %v", err)
+		}
+	}()
+
+	// Graceful shutdown
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Println("🛑 Shutting down server gracefully...")
+	watcher.Stop()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Fatalf("❌ Server forced to shutdown: %v", err)
+	}
+
+	if snapshotFile != "" {
+		saved, err := cacheManager.SaveSnapshot(snapshotFile)
+		if err != nil {
+			log.Printf("⚠️  Failed to write cache snapshot: %v", err)
+		} else {
+			log.Printf("💾 Saved %d cache entries to %s", saved, snapshotFile)
+		}
+	}
+
+	if err := cacheManager.Close(); err != nil {
+		log.Printf("⚠️  Failed to close cache: %v", err)
+	}
+
+	log.Println("✅ Server exited gracefully")
+	return 0
+}
+
+// newCacheManager creates the cache described by the config: process memory,
+// or Redis behind a small in-memory L1
+func newCacheManager(cfg *config.Config) (*cache.CacheManager, error) {
+	var cacheBackend cache.Backend
+	if cfg.CacheBackend == "redis" {
+		redisBackend, err := cache.NewRedisBackend(cache.RedisOptions{
+			Addr:      cfg.Redis.Addr,
+			Password:  cfg.Redis.Password,
+			DB:        cfg.Redis.DB,
+			KeyPrefix: cfg.Redis.KeyPrefix,
+		})
+		if err != nil {
+			return nil, err
+		}
+		l1TTL := time.Duration(cfg.Redis.L1TTLSeconds) * time.Second
+		l1 := cache.NewBoundedMemoryBackend(cfg.Redis.L1MaxEntries, cfg.CacheMaxBytes)
+		cacheBackend, err = cache.NewTieredBackend(l1, l1TTL, redisBackend)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("🗄️  Using Redis cache at %s with %v in-memory L1", cfg.Redis.Addr, l1TTL)
+	} else {
+		cacheBackend = cache.NewBoundedMemoryBackend(cfg.CacheMaxEntries, cfg.CacheMaxBytes)
+	}
+	cacheManager := cache.NewCacheManagerWithBackend(time.Duration(cfg.CacheExpiryMinutes)*time.Minute, cacheBackend)
+	cacheManager.SetNegativeCacheTime(time.Duration(cfg.NegativeCacheMinutes) * time.Minute)
+	return cacheManager, nil
+}
+
+// newWeatherService creates the weather service described by the config
+func newWeatherService(cfg *config.Config, cacheManager *cache.CacheManager, metricsManager *metrics.MetricsManager) (*services.WeatherService, error) {
+	keyer, err := cfg.LocationCache.Keyer()
+	if err != nil {
+		return nil, err
+	}
+	weatherClient := openweathermap.NewClientWithBaseURL(cfg.OpenWeatherMapApiKey, cfg.BaseURL)
+	weatherService := services.NewWeatherService(weatherClient, cacheManager, metricsManager)
+	weatherService.SetDataTTLs(time.Duration(cfg.UVCacheMinutes)*time.Minute, time.Duration(cfg.AirQualityCacheMins)*time.Minute)
+	weatherService.SetLocationKeyer(keyer)
+	return weatherService, nil
+}
+
+// tenantsFromConfig builds the tenant registry entries described by the config
+func tenantsFromConfig(cfg *config.Config) []*tenant.Tenant {
+	tenants := make([]*tenant.Tenant, 0, len(cfg.Tenants))
+	for _, tc := range cfg.Tenants {
+		tenants = append(tenants, &tenant.Tenant{
+			ID:                 tc.ID,
+			Credentials:        tc.APIKeys,
+			APIKey:             tc.OpenWeatherMapApiKey,
+			RateLimitPerMinute: tc.RateLimitPerMinute,
+			CacheNamespace:     tc.CacheNamespace,
+			AllowedEndpoints:   tc.AllowedEndpoints,
+		})
+	}
+	return tenants
+}
+
+// corsOptions builds the CORS policy described by the config. Allowed methods
+// default to those served by the router.
+func corsOptions(cfg *config.Config, router *mux.Router) middleware.CORSOptions {
+	methods := cfg.CORS.AllowedMethods
+	if len(methods) == 0 {
+		methods = middleware.RouteMethods(router)
+	}
+	return middleware.CORSOptions{
+		AllowedOrigins:   cfg.CORS.AllowedOrigins,
+		AllowedMethods:   methods,
+		AllowedHeaders:   cfg.CORS.AllowedHeaders,
+		AllowCredentials: cfg.CORS.AllowCredentials,
+		MaxAgeSeconds:    cfg.CORS.MaxAgeSeconds,
+	}
+}
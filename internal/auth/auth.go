@@ -0,0 +1,136 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Standard scopes checked by the HTTP layer
+const (
+	ScopeWeatherRead = "weather:read"
+	ScopeAdmin       = "weather:admin"
+)
+
+// signingMethods lists the asymmetric algorithms accepted in tokens
+var signingMethods = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+	"EdDSA",
+}
+
+// Options configures a Verifier
+type Options struct {
+	JWKSURL  string
+	KeyFile  string
+	Issuer   string
+	Audience string
+	Leeway   time.Duration
+}
+
+// Claims represents the JWT claims used by the service
+type Claims struct {
+	jwt.RegisteredClaims
+	Scope  string           `json:"scope,omitempty"`
+	Scp    jwt.ClaimStrings `json:"scp,omitempty"`
+	Tenant string           `json:"tenant,omitempty"`
+}
+
+// Scopes returns the union of the "scope" and "scp" claims
+func (c *Claims) Scopes() []string {
+	scopes := strings.Fields(c.Scope)
+	for _, s := range c.Scp {
+		scopes = append(scopes, strings.Fields(s)...)
+	}
+	return scopes
+}
+
+// HasScope reports whether the claims grant the given scope.
+// The admin scope implies every other scope.
+func (c *Claims) HasScope(scope string) bool {
+	for _, s := range c.Scopes() {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// keySource resolves the public key used to verify a token
+type keySource interface {
+	Key(kid string) (crypto.PublicKey, error)
+}
+
+// Verifier validates bearer tokens
+type Verifier struct {
+	keys    keySource
+	options []jwt.ParserOption
+}
+
+// NewVerifier creates a verifier backed by a JWKS URL or a local key file.
+// The issuer and audience are required, so tokens the same identity provider
+// minted for other services are rejected.
+func NewVerifier(opts Options) (*Verifier, error) {
+	if opts.Issuer == "" || opts.Audience == "" {
+		return nil, fmt.Errorf("an issuer and an audience are required")
+	}
+
+	var keys keySource
+	switch {
+	case opts.JWKSURL != "":
+		keys = newJWKSSource(opts.JWKSURL)
+	case opts.KeyFile != "":
+		fileKeys, err := loadKeyFile(opts.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = fileKeys
+	default:
+		return nil, fmt.Errorf("either a JWKS URL or a key file is required")
+	}
+
+	if opts.Leeway == 0 {
+		opts.Leeway = 30 * time.Second
+	}
+
+	parserOptions := []jwt.ParserOption{
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(opts.Leeway),
+		jwt.WithIssuer(opts.Issuer),
+		jwt.WithAudience(opts.Audience),
+	}
+
+	return &Verifier{keys: keys, options: parserOptions}, nil
+}
+
+// Verify parses a raw token and validates its signature, issuer, audience and expiry
+func (v *Verifier) Verify(raw string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(raw, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return v.keys.Key(kid)
+	}, v.options...)
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+type claimsContextKey struct{}
+
+// WithClaims returns a copy of ctx carrying the verified claims
+func WithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsContextKey{}, claims)
+}
+
+// ClaimsFromContext returns the verified claims stored in ctx, if any
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsContextKey{}).(*Claims)
+	return claims, ok
+}
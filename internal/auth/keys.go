@@ -0,0 +1,279 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// jwksRefreshInterval is how long fetched keys are trusted before a refetch
+	jwksRefreshInterval = 1 * time.Hour
+	// jwksMinRefetch throttles refetches triggered by unknown key IDs
+	jwksMinRefetch = 1 * time.Minute
+)
+
+// jsonWebKey is a single entry of a JWKS document
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// jwksSource fetches and caches keys from a JWKS endpoint. Lookups only take
+// a read lock; the endpoint is fetched outside the lock, once for all callers
+// waiting on the same refresh.
+type jwksSource struct {
+	url        string
+	httpClient *http.Client
+	keys       map[string]crypto.PublicKey
+	fetchedAt  time.Time
+	inflight   *jwksFetch
+	mu         sync.RWMutex
+}
+
+// jwksFetch is a refresh in progress. err is set before done is closed.
+type jwksFetch struct {
+	done chan struct{}
+	err  error
+}
+
+func newJWKSSource(url string) *jwksSource {
+	return &jwksSource{
+		url: url,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		keys: make(map[string]crypto.PublicKey),
+	}
+}
+
+// Key returns the key for kid, refreshing the key set when it is stale or kid is unknown
+func (s *jwksSource) Key(kid string) (crypto.PublicKey, error) {
+	s.mu.RLock()
+	fetchedAt := s.fetchedAt
+	key, found := s.lookup(kid)
+	s.mu.RUnlock()
+
+	stale := time.Since(fetchedAt) > jwksRefreshInterval
+	if found && !stale {
+		return key, nil
+	}
+
+	if stale || time.Since(fetchedAt) > jwksMinRefetch {
+		if err := s.refresh(fetchedAt); err != nil {
+			if found {
+				return key, nil
+			}
+			return nil, err
+		}
+		s.mu.RLock()
+		key, found = s.lookup(kid)
+		s.mu.RUnlock()
+	}
+
+	if !found {
+		return nil, fmt.Errorf("no signing key found for kid '%s'", kid)
+	}
+	return key, nil
+}
+
+// lookup finds a key by kid; an empty kid matches only a single-key set.
+// The caller must hold s.mu.
+func (s *jwksSource) lookup(kid string) (crypto.PublicKey, bool) {
+	if kid == "" {
+		if len(s.keys) == 1 {
+			for _, key := range s.keys {
+				return key, true
+			}
+		}
+		return nil, false
+	}
+	key, found := s.keys[kid]
+	return key, found
+}
+
+// refresh fetches the key set unless it changed since the caller saw it at
+// seen. Concurrent callers share a single fetch.
+func (s *jwksSource) refresh(seen time.Time) error {
+	s.mu.Lock()
+	if s.fetchedAt.After(seen) {
+		s.mu.Unlock()
+		return nil
+	}
+	if fetch := s.inflight; fetch != nil {
+		s.mu.Unlock()
+		<-fetch.done
+		return fetch.err
+	}
+	fetch := &jwksFetch{done: make(chan struct{})}
+	s.inflight = fetch
+	s.mu.Unlock()
+
+	keys, err := s.fetch()
+
+	s.mu.Lock()
+	if err == nil {
+		s.keys = keys
+		s.fetchedAt = time.Now()
+	}
+	s.inflight = nil
+	s.mu.Unlock()
+
+	fetch.err = err
+	close(fetch.done)
+	return err
+}
+
+// fetch downloads and parses the key set
+func (s *jwksSource) fetch() (map[string]crypto.PublicKey, error) {
+	resp, err := s.httpClient.Get(s.url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("JWKS endpoint returned status: %s", resp.Status)
+	}
+
+	var doc struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS: %v", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(doc.Keys))
+	for _, jwk := range doc.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			// Skip keys we cannot use rather than failing the whole set
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	return keys, nil
+}
+
+// publicKey converts a JWK into a Go public key
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve: %s", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve: %s", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type: %s", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// fileSource serves a single public key loaded from a PEM file
+type fileSource struct {
+	key crypto.PublicKey
+}
+
+func loadKeyFile(filename string) (*fileSource, error) {
+	bytes, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %v", err)
+	}
+
+	block, _ := pem.Decode(bytes)
+	if block == nil {
+		return nil, fmt.Errorf("key file '%s' does not contain a PEM block", filename)
+	}
+
+	var key crypto.PublicKey
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		cert, err = x509.ParseCertificate(block.Bytes)
+		if err == nil {
+			key = cert.PublicKey
+		}
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type: %s", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse key file: %v", err)
+	}
+
+	return &fileSource{key: key}, nil
+}
+
+// Key returns the configured key regardless of kid
+func (s *fileSource) Key(kid string) (crypto.PublicKey, error) {
+	return s.key, nil
+}
@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/models"
+)
+
+// Entry is a cached value together with when it was stored and when it
+// expires. StoredAt is zero when the backend cannot tell.
+type Entry struct {
+	Data      models.WeatherData
+	StoredAt  time.Time
+	ExpiresAt time.Time
+}
+
+// Backend is a storage engine for cached weather data. Implementations must
+// be safe for concurrent use and must never return expired entries.
+type Backend interface {
+	// Get returns the entry for key if it exists and has not expired
+	Get(key string) (Entry, bool, error)
+	// Set stores data under key for the given time to live
+	Set(key string, data models.WeatherData, ttl time.Duration) error
+	// Delete removes a single key
+	Delete(key string) error
+	// Clear removes every key owned by the backend
+	Clear() error
+	// Entries returns the expiry time of every unexpired key
+	Entries() (map[string]time.Time, error)
+	// Close releases resources held by the backend
+	Close() error
+}
+
+// Invalidation announces keys removed from a shared cache. All is set when
+// the whole cache was cleared.
+type Invalidation struct {
+	Origin string `json:"origin"`
+	Key    string `json:"key,omitempty"`
+	All    bool   `json:"all,omitempty"`
+}
+
+// Invalidator is implemented by shared backends that can broadcast
+// invalidations to every replica
+type Invalidator interface {
+	PublishInvalidation(inv Invalidation) error
+	SubscribeInvalidations(handler func(Invalidation)) (stop func(), err error)
+}
+
+// StatsProvider is implemented by backends that report their own statistics.
+// The returned fields are reported under "backend" by CacheManager.GetStats.
+type StatsProvider interface {
+	Stats() map[string]interface{}
+}
+
+// Counter is implemented by backends that can count keys without reading
+// their expiry times
+type Counter interface {
+	// Count returns the number of keys starting with prefix
+	Count(prefix string) (int, error)
+}
+
+// Lister is implemented by backends that can list a bounded number of keys
+type Lister interface {
+	// EntriesWithPrefix returns the expiry time of at most limit keys starting
+	// with prefix, and whether other keys were left out
+	EntriesWithPrefix(prefix string, limit int) (map[string]time.Time, bool, error)
+}
+
+// CountEntries counts the keys of backend starting with prefix, using
+// Counter when the backend implements it
+func CountEntries(backend Backend, prefix string) (int, error) {
+	if counter, ok := backend.(Counter); ok {
+		return counter.Count(prefix)
+	}
+	entries, err := backend.Entries()
+	count := 0
+	for key := range entries {
+		if strings.HasPrefix(key, prefix) {
+			count++
+		}
+	}
+	return count, err
+}
+
+// ListEntries returns the expiry time of at most limit keys of backend
+// starting with prefix, and whether other keys were left out, using Lister
+// when the backend implements it
+func ListEntries(backend Backend, prefix string, limit int) (map[string]time.Time, bool, error) {
+	if lister, ok := backend.(Lister); ok {
+		return lister.EntriesWithPrefix(prefix, limit)
+	}
+	all, err := backend.Entries()
+	entries := make(map[string]time.Time)
+	truncated := false
+	for key, expiry := range all {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if len(entries) == limit {
+			truncated = true
+			break
+		}
+		entries[key] = expiry
+	}
+	return entries, truncated, err
+}
+
+// Evictor is implemented by bounded backends that evict live entries
+type Evictor interface {
+	Evictions() int64
+}
@@ -1,143 +1,287 @@
 package cache
 
 import (
+	"fmt"
 	"log"
-	"sync"
+	"path"
+	"sort"
+	"strings"
+	"sync/atomic"
 	"time"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/logging"
 	"github.com/Vivek-Prakash1307/weather-Microservices/internal/models"
 )
 
-// CacheManager handles caching of weather data
+// MaxListedEntries caps how many keys GetStats lists, so inspecting a large
+// shared cache does not look up the expiry of every key
+const MaxListedEntries = 1000
+
+// CacheManager handles caching of weather data. Hit and miss counters and
+// TTLs are atomics so lookups never serialize on a lock and TTLs can be
+// changed by a config reload while serving.
 type CacheManager struct {
-	data      map[string]models.WeatherData
-	expiry    map[string]time.Time
-	mu        sync.RWMutex
-	cacheTime time.Duration
-	hitCount  int64
-	missCount int64
+	backend      Backend
+	cacheTime    atomic.Int64
+	hitCount     atomic.Int64
+	missCount    atomic.Int64
+	negative     *MemoryBackend
+	negativeTime atomic.Int64
+	negativeHits atomic.Int64
 }
 
-// NewCacheManager creates a new cache manager
+// NewCacheManager creates a new cache manager backed by process memory
 func NewCacheManager(cacheTime time.Duration) *CacheManager {
+	return NewCacheManagerWithBackend(cacheTime, NewMemoryBackend())
+}
+
+// NewCacheManagerWithBackend creates a cache manager on top of the given backend
+func NewCacheManagerWithBackend(cacheTime time.Duration, backend Backend) *CacheManager {
 	cm := &CacheManager{
-		data:      make(map[string]models.WeatherData),
-		expiry:    make(map[string]time.Time),
-		cacheTime: cacheTime,
+		backend:  backend,
+		negative: NewBoundedMemoryBackend(maxNegativeEntries, 0),
 	}
+	cm.cacheTime.Store(int64(cacheTime))
+	cm.negativeTime.Store(int64(defaultNegativeCacheTime))
 
-	// Start cleanup goroutine
-	go cm.cleanupExpired()
-
-	log.Printf("✅ Cache initialized with %v expiry time", cacheTime)
+	if logging.Enabled(logging.Info) {
+		log.Printf("✅ Cache initialized with %v expiry time", cacheTime)
+	}
 	return cm
 }
 
+// CacheTime returns the default expiry for weather entries
+func (cm *CacheManager) CacheTime() time.Duration {
+	return time.Duration(cm.cacheTime.Load())
+}
+
+// SetCacheTime changes the default expiry for entries written from now on
+func (cm *CacheManager) SetCacheTime(cacheTime time.Duration) {
+	cm.cacheTime.Store(int64(cacheTime))
+}
+
 // Get retrieves data from cache
 func (cm *CacheManager) Get(key string) (models.WeatherData, bool) {
-	cm.mu.RLock()
-	defer cm.mu.RUnlock()
-
-	if data, found := cm.data[key]; found && time.Now().Before(cm.expiry[key]) {
-		cm.hitCount++
-		return data, true
+	entry, found, err := cm.backend.Get(key)
+	if err != nil {
+		log.Printf("⚠️  Cache read failed for '%s': %v", key, err)
+	}
+	if found {
+		cm.hitCount.Add(1)
+		entry.Data.ExpiresAt = entry.ExpiresAt
+		return entry.Data, true
 	}
 
-	cm.missCount++
+	cm.missCount.Add(1)
 	return models.WeatherData{}, false
 }
 
-// Set stores data in cache
-func (cm *CacheManager) Set(key string, data models.WeatherData) {
-	cm.mu.Lock()
-	defer cm.mu.Unlock()
+// Set stores data in cache and returns when it expires
+func (cm *CacheManager) Set(key string, data models.WeatherData) time.Time {
+	return cm.SetWithTTL(key, data, cm.CacheTime())
+}
 
-	cm.data[key] = data
-	cm.expiry[key] = time.Now().Add(cm.cacheTime)
+// SetWithTTL stores data in cache with its own expiry instead of the default
+// and returns when it expires
+func (cm *CacheManager) SetWithTTL(key string, data models.WeatherData, ttl time.Duration) time.Time {
+	expiresAt := time.Now().Add(ttl)
+	if err := cm.backend.Set(key, data, ttl); err != nil {
+		log.Printf("⚠️  Cache write failed for '%s': %v", key, err)
+	}
+	return expiresAt
 }
 
-// Clear removes all cached data
-func (cm *CacheManager) Clear() {
-	cm.mu.Lock()
-	defer cm.mu.Unlock()
+// Peek returns the full entry for key without counting a hit or a miss
+func (cm *CacheManager) Peek(key string) (Entry, bool) {
+	entry, found, err := cm.backend.Get(key)
+	if err != nil {
+		log.Printf("⚠️  Cache read failed for '%s': %v", key, err)
+	}
+	return entry, found
+}
 
-	cm.data = make(map[string]models.WeatherData)
-	cm.expiry = make(map[string]time.Time)
-	log.Println("🗑️  Cache cleared")
+// Keys returns every cached weather key in sorted order
+func (cm *CacheManager) Keys() ([]string, error) {
+	expiries, err := cm.backend.Entries()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cache entries: %v", err)
+	}
+	keys := make([]string, 0, len(expiries))
+	for key := range expiries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
 }
 
-// GetStats returns cache statistics
-func (cm *CacheManager) GetStats() map[string]interface{} {
-	cm.mu.RLock()
-	defer cm.mu.RUnlock()
+// Delete removes a single key, including a negative entry under that key
+func (cm *CacheManager) Delete(key string) error {
+	cm.negative.Delete(key)
+	if err := cm.backend.Delete(key); err != nil {
+		return fmt.Errorf("failed to delete '%s': %v", key, err)
+	}
+	return nil
+}
 
-	entries := make(map[string]string)
-	validEntries := 0
-	now := time.Now()
+// InvalidatePrefix removes every key starting with prefix and returns how
+// many were removed
+func (cm *CacheManager) InvalidatePrefix(prefix string) (int, error) {
+	return cm.invalidate(func(key string) bool {
+		return strings.HasPrefix(key, prefix)
+	})
+}
 
-	for key, expiry := range cm.expiry {
-		if now.Before(expiry) {
-			entries[key] = expiry.Format("2006-01-02 15:04:05")
-			validEntries++
+// InvalidatePattern removes every key matching a glob pattern such as
+// "acme:*" or "uv:grid:51.5*" and returns how many were removed
+func (cm *CacheManager) InvalidatePattern(pattern string) (int, error) {
+	return cm.InvalidatePatternIn("", pattern)
+}
+
+// InvalidatePatternIn removes every key starting with namespace that matches
+// a glob pattern, so a tenant's pattern cannot reach other tenants' keys
+func (cm *CacheManager) InvalidatePatternIn(namespace, pattern string) (int, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return 0, fmt.Errorf("invalid pattern '%s': %v", pattern, err)
+	}
+	return cm.invalidate(func(key string) bool {
+		matched, _ := path.Match(pattern, key)
+		return matched && strings.HasPrefix(key, namespace)
+	})
+}
+
+func (cm *CacheManager) invalidate(match func(key string) bool) (int, error) {
+	expiries, err := cm.backend.Entries()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list cache entries: %v", err)
+	}
+	negative, _ := cm.negative.Entries()
+	for key := range negative {
+		if match(key) {
+			cm.negative.Delete(key)
 		}
 	}
 
-	return map[string]interface{}{
-		"total_entries":  validEntries,
-		"hit_count":      cm.hitCount,
-		"miss_count":     cm.missCount,
-		"hit_rate":       cm.calculateHitRate(),
-		"cache_duration": cm.cacheTime.String(),
-		"entries":        entries,
+	removed := 0
+	for key := range expiries {
+		if !match(key) {
+			continue
+		}
+		if err := cm.backend.Delete(key); err != nil {
+			return removed, fmt.Errorf("failed to delete '%s': %v", key, err)
+		}
+		removed++
 	}
+	if removed > 0 {
+		log.Printf("🗑️  Invalidated %d cache entries", removed)
+	}
+	return removed, nil
 }
 
-// calculateHitRate calculates the cache hit rate percentage
-func (cm *CacheManager) calculateHitRate() float64 {
-	total := cm.hitCount + cm.missCount
-	if total == 0 {
-		return 0
+// ExpiresAt returns the expiry time of a cached entry
+func (cm *CacheManager) ExpiresAt(key string) (time.Time, bool) {
+	entry, found, err := cm.backend.Get(key)
+	if err != nil || !found {
+		return time.Time{}, false
+	}
+	return entry.ExpiresAt, true
+}
+
+// Clear removes all cached data, including negative entries
+func (cm *CacheManager) Clear() {
+	cm.negative.Clear()
+	if err := cm.backend.Clear(); err != nil {
+		log.Printf("⚠️  Cache clear failed: %v", err)
+		return
 	}
-	return float64(cm.hitCount) / float64(total) * 100
+	log.Println("🗑️  Cache cleared")
 }
 
-// cleanupExpired removes expired entries periodically
-func (cm *CacheManager) cleanupExpired() {
-	ticker := time.NewTicker(5 * time.Minute)
-	defer ticker.Stop()
+// Close releases the cache backends
+func (cm *CacheManager) Close() error {
+	cm.negative.Close()
+	return cm.backend.Close()
+}
 
-	for range ticker.C {
-		cm.mu.Lock()
-		now := time.Now()
-		cleaned := 0
+// GetStats returns cache statistics. Statistics reported by the backend
+// itself are nested under "backend" so they cannot shadow the common ones.
+func (cm *CacheManager) GetStats() map[string]interface{} {
+	return cm.GetStatsIn("")
+}
 
-		for key, expiry := range cm.expiry {
-			if now.After(expiry) {
-				delete(cm.data, key)
-				delete(cm.expiry, key)
-				cleaned++
-			}
+// GetStatsIn returns cache statistics for the keys starting with namespace.
+// At most MaxListedEntries keys are listed; total_entries counts them all.
+// For a non-empty namespace the hit and miss counters and the shared
+// backend's own statistics are left out, since they cover every namespace.
+func (cm *CacheManager) GetStatsIn(namespace string) map[string]interface{} {
+	entries := make(map[string]string)
+	expiries, truncated, err := ListEntries(cm.backend, namespace, MaxListedEntries)
+	if err != nil {
+		log.Printf("⚠️  Failed to list cache entries: %v", err)
+	}
+	for key, expiry := range expiries {
+		entries[key] = expiry.Format("2006-01-02 15:04:05")
+	}
+	total := len(entries)
+	if truncated {
+		if total, err = CountEntries(cm.backend, namespace); err != nil {
+			log.Printf("⚠️  Failed to count cache entries: %v", err)
 		}
+	}
 
-		if cleaned > 0 {
-			log.Printf("🧹 Cleaned %d expired cache entries", cleaned)
+	negativeEntries, _ := cm.negative.Entries()
+	negativeCount := 0
+	for key := range negativeEntries {
+		if strings.HasPrefix(key, namespace) {
+			negativeCount++
 		}
-		cm.mu.Unlock()
 	}
-}
 
-// GetSize returns the number of cached entries
-func (cm *CacheManager) GetSize() int {
-	cm.mu.RLock()
-	defer cm.mu.RUnlock()
+	stats := map[string]interface{}{
+		"total_entries":           total,
+		"cache_duration":          cm.CacheTime().String(),
+		"entries":                 entries,
+		"entries_truncated":       truncated,
+		"negative_entries":        negativeCount,
+		"negative_cache_duration": time.Duration(cm.negativeTime.Load()).String(),
+	}
+	if namespace != "" {
+		stats["namespace"] = namespace
+		return stats
+	}
+	hits, misses := cm.hitCount.Load(), cm.missCount.Load()
+	stats["hit_count"] = hits
+	stats["miss_count"] = misses
+	stats["hit_rate"] = calculateHitRate(hits, misses)
+	stats["negative_hit_count"] = cm.negativeHits.Load()
+	if provider, ok := cm.backend.(StatsProvider); ok {
+		stats["backend"] = provider.Stats()
+	}
+	return stats
+}
 
-	validEntries := 0
-	now := time.Now()
+// Evictions returns the number of live entries evicted by a bounded backend
+func (cm *CacheManager) Evictions() int64 {
+	if evictor, ok := cm.backend.(Evictor); ok {
+		return evictor.Evictions()
+	}
+	return 0
+}
 
-	for _, expiry := range cm.expiry {
-		if now.Before(expiry) {
-			validEntries++
-		}
+// calculateHitRate calculates the cache hit rate percentage
+func calculateHitRate(hits, misses int64) float64 {
+	total := hits + misses
+	if total == 0 {
+		return 0
 	}
+	return float64(hits) / float64(total) * 100
+}
 
-	return validEntries
-}
\ No newline at end of file
+// GetSize returns the number of cached entries without reading their expiry
+// times where the backend can count them directly
+func (cm *CacheManager) GetSize() int {
+	count, err := CountEntries(cm.backend, "")
+	if err != nil {
+		log.Printf("⚠️  Failed to count cache entries: %v", err)
+	}
+	return count
+}
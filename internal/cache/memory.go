@@ -0,0 +1,368 @@
+package cache
+
+import (
+	"container/list"
+	"encoding/json"
+	"hash/fnv"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/models"
+)
+
+const (
+	// entryOverhead approximates the bookkeeping cost of an entry beyond its payload
+	entryOverhead = 128
+	// maxShards caps the number of independently locked partitions
+	maxShards = 16
+	// minShardEntries and minShardBytes keep bounded shards large enough
+	// that per-shard LRU stays close to a global LRU
+	minShardEntries = 64
+	minShardBytes   = 64 << 10
+	// sweepInterval is how long a full expiry sweep over every shard takes
+	sweepInterval = 5 * time.Minute
+)
+
+// memoryItem is an entry plus the time it is dropped from memory, which may
+// be earlier than the entry's own expiry when used as a short-lived L1
+type memoryItem struct {
+	key     string
+	entry   Entry
+	evictAt time.Time
+	size    int64
+}
+
+// memoryShard is one independently locked LRU partition of a MemoryBackend
+type memoryShard struct {
+	data       map[string]*list.Element
+	lru        *list.List
+	maxEntries int
+	maxBytes   int64
+	bytes      int64
+	evictions  int64
+	mu         sync.Mutex
+}
+
+// MemoryBackend stores entries in an in-process map partitioned into shards
+// by key hash. Each shard evicts its least recently used entries once its
+// share of the configured limits is exceeded.
+type MemoryBackend struct {
+	shards     []*memoryShard
+	maxEntries int
+	maxBytes   int64
+	done       chan struct{}
+	once       sync.Once
+}
+
+// NewMemoryBackend creates an unbounded in-memory backend
+func NewMemoryBackend() *MemoryBackend {
+	return NewBoundedMemoryBackend(0, 0)
+}
+
+// NewBoundedMemoryBackend creates an in-memory backend holding at most
+// maxEntries entries and maxBytes of estimated payload. Zero disables a limit.
+func NewBoundedMemoryBackend(maxEntries int, maxBytes int64) *MemoryBackend {
+	shardCount := maxShards
+	if maxEntries > 0 && maxEntries/minShardEntries < shardCount {
+		shardCount = maxEntries / minShardEntries
+	}
+	if maxBytes > 0 && int(maxBytes/minShardBytes) < shardCount {
+		shardCount = int(maxBytes / minShardBytes)
+	}
+	if shardCount < 1 {
+		shardCount = 1
+	}
+	return NewShardedMemoryBackend(shardCount, maxEntries, maxBytes)
+}
+
+// NewShardedMemoryBackend creates an in-memory backend with an explicit
+// shard count; the limits are divided evenly between shards
+func NewShardedMemoryBackend(shardCount, maxEntries int, maxBytes int64) *MemoryBackend {
+	if shardCount < 1 {
+		shardCount = 1
+	}
+
+	mb := &MemoryBackend{
+		shards:     make([]*memoryShard, shardCount),
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		done:       make(chan struct{}),
+	}
+	for i := range mb.shards {
+		mb.shards[i] = &memoryShard{
+			data:       make(map[string]*list.Element),
+			lru:        list.New(),
+			maxEntries: ceilDiv(int64(maxEntries), int64(shardCount)),
+			maxBytes:   int64(ceilDiv(maxBytes, int64(shardCount))),
+		}
+	}
+
+	// Start cleanup goroutine
+	go mb.cleanupExpired()
+
+	return mb
+}
+
+func ceilDiv(a, b int64) int {
+	return int((a + b - 1) / b)
+}
+
+// shard returns the partition owning key
+func (mb *MemoryBackend) shard(key string) *memoryShard {
+	if len(mb.shards) == 1 {
+		return mb.shards[0]
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return mb.shards[h.Sum32()%uint32(len(mb.shards))]
+}
+
+// Get returns an unexpired entry and marks it as recently used
+func (mb *MemoryBackend) Get(key string) (Entry, bool, error) {
+	s := mb.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, found := s.data[key]
+	if !found {
+		return Entry{}, false, nil
+	}
+
+	item := element.Value.(*memoryItem)
+	if !time.Now().Before(item.evictAt) {
+		s.remove(element)
+		return Entry{}, false, nil
+	}
+
+	s.lru.MoveToFront(element)
+	return item.entry, true, nil
+}
+
+// Set stores data for the given duration
+func (mb *MemoryBackend) Set(key string, data models.WeatherData, ttl time.Duration) error {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	mb.shard(key).store(key, Entry{Data: data, StoredAt: now, ExpiresAt: expiresAt}, expiresAt)
+	return nil
+}
+
+// SetEntry stores an entry that keeps its own expiry but is held in memory
+// for at most ttl
+func (mb *MemoryBackend) SetEntry(key string, entry Entry, ttl time.Duration) {
+	evictAt := time.Now().Add(ttl)
+	if entry.ExpiresAt.Before(evictAt) {
+		evictAt = entry.ExpiresAt
+	}
+	mb.shard(key).store(key, entry, evictAt)
+}
+
+func (s *memoryShard) store(key string, entry Entry, evictAt time.Time) {
+	size := estimateSize(key, entry.Data)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, found := s.data[key]; found {
+		item := element.Value.(*memoryItem)
+		s.bytes += size - item.size
+		item.entry, item.evictAt, item.size = entry, evictAt, size
+		s.lru.MoveToFront(element)
+	} else {
+		s.data[key] = s.lru.PushFront(&memoryItem{key: key, entry: entry, evictAt: evictAt, size: size})
+		s.bytes += size
+	}
+
+	for s.overLimit() {
+		s.remove(s.lru.Back())
+		s.evictions++
+	}
+}
+
+// overLimit reports whether a limit is exceeded; the newest entry is always kept
+func (s *memoryShard) overLimit() bool {
+	if s.lru.Len() <= 1 {
+		return false
+	}
+	return (s.maxEntries > 0 && s.lru.Len() > s.maxEntries) ||
+		(s.maxBytes > 0 && s.bytes > s.maxBytes)
+}
+
+// remove unlinks an element; callers must hold the shard lock
+func (s *memoryShard) remove(element *list.Element) {
+	item := s.lru.Remove(element).(*memoryItem)
+	delete(s.data, item.key)
+	s.bytes -= item.size
+}
+
+// sweep removes expired entries from the shard and returns how many were removed
+func (s *memoryShard) sweep(now time.Time) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cleaned := 0
+	for _, element := range s.data {
+		if now.After(element.Value.(*memoryItem).evictAt) {
+			s.remove(element)
+			cleaned++
+		}
+	}
+	return cleaned
+}
+
+// estimateSize approximates the memory held by an entry from its encoded size
+func estimateSize(key string, data models.WeatherData) int64 {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return int64(len(key)) + entryOverhead
+	}
+	return int64(len(key)+len(encoded)) + entryOverhead
+}
+
+// Delete removes a single key
+func (mb *MemoryBackend) Delete(key string) error {
+	s := mb.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, found := s.data[key]; found {
+		s.remove(element)
+	}
+	return nil
+}
+
+// Clear removes all entries
+func (mb *MemoryBackend) Clear() error {
+	for _, s := range mb.shards {
+		s.mu.Lock()
+		s.data = make(map[string]*list.Element)
+		s.lru.Init()
+		s.bytes = 0
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+// Entries returns the expiry time of every unexpired key
+func (mb *MemoryBackend) Entries() (map[string]time.Time, error) {
+	entries := make(map[string]time.Time)
+	now := time.Now()
+	for _, s := range mb.shards {
+		s.mu.Lock()
+		for key, element := range s.data {
+			item := element.Value.(*memoryItem)
+			if now.Before(item.evictAt) {
+				entries[key] = item.entry.ExpiresAt
+			}
+		}
+		s.mu.Unlock()
+	}
+	return entries, nil
+}
+
+// Count returns the number of unexpired keys starting with prefix
+func (mb *MemoryBackend) Count(prefix string) (int, error) {
+	count := 0
+	mb.visit(prefix, func(string, *memoryItem) bool {
+		count++
+		return true
+	})
+	return count, nil
+}
+
+// EntriesWithPrefix returns the expiry time of at most limit unexpired keys
+// starting with prefix, and whether other keys were left out
+func (mb *MemoryBackend) EntriesWithPrefix(prefix string, limit int) (map[string]time.Time, bool, error) {
+	entries := make(map[string]time.Time)
+	truncated := false
+	mb.visit(prefix, func(key string, item *memoryItem) bool {
+		if len(entries) == limit {
+			truncated = true
+			return false
+		}
+		entries[key] = item.entry.ExpiresAt
+		return true
+	})
+	return entries, truncated, nil
+}
+
+// visit calls fn for every unexpired key starting with prefix, one shard at
+// a time, until fn returns false
+func (mb *MemoryBackend) visit(prefix string, fn func(key string, item *memoryItem) bool) {
+	now := time.Now()
+	for _, s := range mb.shards {
+		s.mu.Lock()
+		for key, element := range s.data {
+			item := element.Value.(*memoryItem)
+			if now.Before(item.evictAt) && strings.HasPrefix(key, prefix) && !fn(key, item) {
+				s.mu.Unlock()
+				return
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// Evictions returns the number of entries dropped to stay within the limits
+func (mb *MemoryBackend) Evictions() int64 {
+	var evictions int64
+	for _, s := range mb.shards {
+		s.mu.Lock()
+		evictions += s.evictions
+		s.mu.Unlock()
+	}
+	return evictions
+}
+
+// Stats reports the configured size limits and current usage. The limits are
+// the configured totals, not the sum of the rounded up per-shard shares.
+func (mb *MemoryBackend) Stats() map[string]interface{} {
+	var (
+		entries          int
+		bytes, evictions int64
+	)
+	for _, s := range mb.shards {
+		s.mu.Lock()
+		entries += s.lru.Len()
+		bytes += s.bytes
+		evictions += s.evictions
+		s.mu.Unlock()
+	}
+
+	return map[string]interface{}{
+		"entries":     entries,
+		"bytes":       bytes,
+		"max_entries": mb.maxEntries,
+		"max_bytes":   mb.maxBytes,
+		"evictions":   evictions,
+		"shards":      len(mb.shards),
+	}
+}
+
+// Close stops the cleanup goroutine
+func (mb *MemoryBackend) Close() error {
+	mb.once.Do(func() { close(mb.done) })
+	return nil
+}
+
+// cleanupExpired sweeps one shard per tick so only a single shard is ever
+// locked for a sweep, completing a full pass every sweepInterval
+func (mb *MemoryBackend) cleanupExpired() {
+	ticker := time.NewTicker(sweepInterval / time.Duration(len(mb.shards)))
+	defer ticker.Stop()
+
+	next := 0
+	for {
+		select {
+		case <-mb.done:
+			return
+		case <-ticker.C:
+		}
+
+		if cleaned := mb.shards[next].sweep(time.Now()); cleaned > 0 {
+			log.Printf("🧹 Cleaned %d expired cache entries from shard %d", cleaned, next)
+		}
+		next = (next + 1) % len(mb.shards)
+	}
+}
@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/models"
+)
+
+const (
+	// defaultNegativeCacheTime is how long a "not found" result is remembered
+	defaultNegativeCacheTime = 2 * time.Minute
+	// maxNegativeEntries bounds memory used by misspelled or random city names
+	maxNegativeEntries = 10000
+)
+
+// SetNegativeCacheTime sets how long "not found" results are cached. A ttl of
+// zero or less turns negative caching off and drops the existing entries.
+func (cm *CacheManager) SetNegativeCacheTime(ttl time.Duration) {
+	if ttl <= 0 {
+		cm.negativeTime.Store(0)
+		cm.negative.Clear()
+		return
+	}
+	cm.negativeTime.Store(int64(ttl))
+}
+
+// SetNotFound remembers that key does not exist upstream. Negative entries
+// live in process memory with their own, shorter TTL.
+func (cm *CacheManager) SetNotFound(key string) {
+	ttl := time.Duration(cm.negativeTime.Load())
+	if ttl <= 0 {
+		return
+	}
+	cm.negative.Set(key, models.WeatherData{}, ttl)
+}
+
+// IsNotFound reports whether key is negatively cached
+func (cm *CacheManager) IsNotFound(key string) bool {
+	if _, found, _ := cm.negative.Get(key); found {
+		cm.negativeHits.Add(1)
+		return true
+	}
+	return false
+}
+
+// PeekNotFound reports whether key is negatively cached without counting a
+// negative hit
+func (cm *CacheManager) PeekNotFound(key string) bool {
+	_, found, _ := cm.negative.Get(key)
+	return found
+}
+
+// ForgetNotFound removes the negative entry for key, if any
+func (cm *CacheManager) ForgetNotFound(key string) {
+	cm.negative.Delete(key)
+}
+
+// ClearNegative removes every negative entry, leaving cached weather intact
+func (cm *CacheManager) ClearNegative() {
+	cm.negative.Clear()
+	log.Println("🗑️  Negative cache cleared")
+}
+
+// ClearNegativeIn removes the negative entries starting with namespace. An
+// empty namespace clears every negative entry.
+func (cm *CacheManager) ClearNegativeIn(namespace string) {
+	if namespace == "" {
+		cm.ClearNegative()
+		return
+	}
+	entries, _ := cm.negative.Entries()
+	for key := range entries {
+		if strings.HasPrefix(key, namespace) {
+			cm.negative.Delete(key)
+		}
+	}
+}
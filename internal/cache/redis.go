@@ -0,0 +1,346 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisOptions configures a RedisBackend
+type RedisOptions struct {
+	Addr      string
+	Password  string
+	DB        int
+	KeyPrefix string
+	Timeout   time.Duration
+}
+
+// redisBatchSize is how many keys one SCAN page, DEL or TTL pipeline covers
+const redisBatchSize = 500
+
+// countCacheTime is how long the total key count is reused, so frequent
+// health checks do not each scan the whole keyspace
+const countCacheTime = 10 * time.Second
+
+// RedisBackend stores entries in Redis so every replica shares one cache
+type RedisBackend struct {
+	client  *redis.Client
+	prefix  string
+	timeout time.Duration
+
+	countMu   sync.Mutex
+	count     int
+	countedAt time.Time
+}
+
+// NewRedisBackend connects to Redis and verifies the connection
+func NewRedisBackend(opts RedisOptions) (*RedisBackend, error) {
+	if opts.Addr == "" {
+		return nil, fmt.Errorf("redis address is required")
+	}
+	if opts.KeyPrefix == "" {
+		opts.KeyPrefix = "weather:"
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 2 * time.Second
+	}
+
+	rb := &RedisBackend{
+		client: redis.NewClient(&redis.Options{
+			Addr:         opts.Addr,
+			Password:     opts.Password,
+			DB:           opts.DB,
+			DialTimeout:  opts.Timeout,
+			ReadTimeout:  opts.Timeout,
+			WriteTimeout: opts.Timeout,
+		}),
+		prefix:  opts.KeyPrefix,
+		timeout: opts.Timeout,
+	}
+
+	ctx, cancel := rb.context()
+	defer cancel()
+	if err := rb.client.Ping(ctx).Err(); err != nil {
+		rb.client.Close()
+		return nil, fmt.Errorf("failed to connect to redis at %s: %v", opts.Addr, err)
+	}
+
+	return rb, nil
+}
+
+func (rb *RedisBackend) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), rb.timeout)
+}
+
+// Get fetches and decodes an entry along with its remaining TTL
+func (rb *RedisBackend) Get(key string) (Entry, bool, error) {
+	ctx, cancel := rb.context()
+	defer cancel()
+
+	pipe := rb.client.Pipeline()
+	getCmd := pipe.Get(ctx, rb.prefix+key)
+	ttlCmd := pipe.PTTL(ctx, rb.prefix+key)
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return Entry{}, false, fmt.Errorf("redis get failed: %v", err)
+	}
+
+	payload, err := getCmd.Bytes()
+	if err == redis.Nil {
+		return Entry{}, false, nil
+	} else if err != nil {
+		return Entry{}, false, fmt.Errorf("redis get failed: %v", err)
+	}
+
+	ttl := ttlCmd.Val()
+	if ttl <= 0 {
+		// Keys without an expiry are not written by this backend
+		return Entry{}, false, nil
+	}
+
+	var value redisValue
+	if err := json.Unmarshal(payload, &value); err != nil {
+		return Entry{}, false, fmt.Errorf("failed to decode cached data for '%s': %v", key, err)
+	}
+
+	return Entry{Data: value.WeatherData, StoredAt: value.StoredAt, ExpiresAt: time.Now().Add(ttl)}, true, nil
+}
+
+// redisValue is the stored JSON: the WeatherData fields plus the write time,
+// which replicas running an older version simply ignore
+type redisValue struct {
+	models.WeatherData
+	StoredAt time.Time `json:"_stored_at"`
+}
+
+// Set encodes data as JSON and stores it with the given TTL
+func (rb *RedisBackend) Set(key string, data models.WeatherData, ttl time.Duration) error {
+	payload, err := json.Marshal(redisValue{WeatherData: data, StoredAt: time.Now()})
+	if err != nil {
+		return fmt.Errorf("failed to encode data for '%s': %v", key, err)
+	}
+
+	ctx, cancel := rb.context()
+	defer cancel()
+	if err := rb.client.Set(ctx, rb.prefix+key, payload, ttl).Err(); err != nil {
+		return fmt.Errorf("redis set failed: %v", err)
+	}
+	return nil
+}
+
+// Delete removes a single key
+func (rb *RedisBackend) Delete(key string) error {
+	ctx, cancel := rb.context()
+	defer cancel()
+	return rb.client.Del(ctx, rb.prefix+key).Err()
+}
+
+// Clear removes every key under the backend's prefix without touching other
+// data. Each DEL batch gets its own timeout.
+func (rb *RedisBackend) Clear() error {
+	keys, err := rb.scan()
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(keys); start += redisBatchSize {
+		end := min(start+redisBatchSize, len(keys))
+		ctx, cancel := rb.context()
+		err := rb.client.Del(ctx, keys[start:end]...).Err()
+		cancel()
+		if err != nil {
+			return fmt.Errorf("redis clear failed: %v", err)
+		}
+	}
+
+	rb.countMu.Lock()
+	rb.countedAt = time.Time{}
+	rb.countMu.Unlock()
+	return nil
+}
+
+// Entries returns the expiry time of every key under the prefix
+func (rb *RedisBackend) Entries() (map[string]time.Time, error) {
+	keys, err := rb.scan()
+	if err != nil {
+		return nil, err
+	}
+	return rb.expiries(keys)
+}
+
+// Count returns the number of keys starting with prefix. Only SCAN is used,
+// so counting costs no per-key round trips. The total for an empty prefix is
+// reused for countCacheTime, and concurrent callers share one scan.
+func (rb *RedisBackend) Count(prefix string) (int, error) {
+	if prefix == "" {
+		rb.countMu.Lock()
+		defer rb.countMu.Unlock()
+		if time.Since(rb.countedAt) < countCacheTime {
+			return rb.count, nil
+		}
+	}
+
+	count := 0
+	err := rb.scanPrefix(prefix, func(string) bool {
+		count++
+		return true
+	})
+	if err == nil && prefix == "" {
+		rb.count, rb.countedAt = count, time.Now()
+	}
+	return count, err
+}
+
+// EntriesWithPrefix returns the expiry time of at most limit keys starting
+// with prefix, and whether other keys were left out. The scan stops once
+// limit keys are found, and only those keys have their TTL looked up.
+func (rb *RedisBackend) EntriesWithPrefix(prefix string, limit int) (map[string]time.Time, bool, error) {
+	var keys []string
+	seen := make(map[string]bool)
+	truncated := false
+	err := rb.scanPrefix(prefix, func(key string) bool {
+		if seen[key] {
+			return true
+		}
+		if len(keys) == limit {
+			truncated = true
+			return false
+		}
+		seen[key] = true
+		keys = append(keys, key)
+		return true
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	entries, err := rb.expiries(keys)
+	return entries, truncated, err
+}
+
+// expiries looks up the TTL of every full Redis key, one pipeline per batch,
+// and returns the expiry time by cache key
+func (rb *RedisBackend) expiries(keys []string) (map[string]time.Time, error) {
+	entries := make(map[string]time.Time, len(keys))
+	for start := 0; start < len(keys); start += redisBatchSize {
+		batch := keys[start:min(start+redisBatchSize, len(keys))]
+		ctx, cancel := rb.context()
+		pipe := rb.client.Pipeline()
+		ttlCmds := make([]*redis.DurationCmd, len(batch))
+		for i, key := range batch {
+			ttlCmds[i] = pipe.PTTL(ctx, key)
+		}
+		_, err := pipe.Exec(ctx)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("redis ttl lookup failed: %v", err)
+		}
+
+		now := time.Now()
+		for i, key := range batch {
+			if ttl := ttlCmds[i].Val(); ttl > 0 {
+				entries[strings.TrimPrefix(key, rb.prefix)] = now.Add(ttl)
+			}
+		}
+	}
+	return entries, nil
+}
+
+// Close closes the Redis connection pool
+func (rb *RedisBackend) Close() error {
+	return rb.client.Close()
+}
+
+// scan lists every key under the prefix
+func (rb *RedisBackend) scan() ([]string, error) {
+	var keys []string
+	err := rb.scanPrefix("", func(key string) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys, err
+}
+
+// scanPrefix calls fn with every full Redis key whose cache key starts with
+// prefix until fn returns false. SCAN walks the whole keyspace, so each page
+// gets its own timeout rather than one for the entire scan. SCAN may report
+// a key more than once.
+func (rb *RedisBackend) scanPrefix(prefix string, fn func(key string) bool) error {
+	match := globEscape(rb.prefix+prefix) + "*"
+	var cursor uint64
+	for {
+		ctx, cancel := rb.context()
+		keys, next, err := rb.client.Scan(ctx, cursor, match, redisBatchSize).Result()
+		cancel()
+		if err != nil {
+			return fmt.Errorf("redis scan failed: %v", err)
+		}
+		for _, key := range keys {
+			if !fn(key) {
+				return nil
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// globEscape escapes the characters Redis treats as glob patterns in MATCH
+func globEscape(s string) string {
+	var escaped strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(`*?[]\`, r) {
+			escaped.WriteRune('\\')
+		}
+		escaped.WriteRune(r)
+	}
+	return escaped.String()
+}
+
+// invalidationChannel is the pub/sub channel replicas listen on
+func (rb *RedisBackend) invalidationChannel() string {
+	return rb.prefix + "invalidations"
+}
+
+// PublishInvalidation broadcasts an invalidation to every subscribed replica
+func (rb *RedisBackend) PublishInvalidation(inv Invalidation) error {
+	payload, err := json.Marshal(inv)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := rb.context()
+	defer cancel()
+	return rb.client.Publish(ctx, rb.invalidationChannel(), payload).Err()
+}
+
+// SubscribeInvalidations calls handler for every invalidation published by any replica
+func (rb *RedisBackend) SubscribeInvalidations(handler func(Invalidation)) (func(), error) {
+	ctx, cancel := rb.context()
+	defer cancel()
+
+	pubsub := rb.client.Subscribe(context.Background(), rb.invalidationChannel())
+	// Wait for the subscription to be confirmed so no invalidation is missed
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, fmt.Errorf("failed to subscribe to invalidations: %v", err)
+	}
+
+	go func() {
+		for msg := range pubsub.Channel() {
+			var inv Invalidation
+			if err := json.Unmarshal([]byte(msg.Payload), &inv); err != nil {
+				continue
+			}
+			handler(inv)
+		}
+	}()
+
+	return func() { pubsub.Close() }, nil
+}
@@ -0,0 +1,166 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/models"
+)
+
+// snapshotVersion is bumped whenever the snapshot file layout changes
+const snapshotVersion = 1
+
+// snapshotFile is the on-disk snapshot format
+type snapshotFile struct {
+	Version   int             `json:"version"`
+	Schema    string          `json:"schema"`
+	CreatedAt time.Time       `json:"created_at"`
+	Entries   []snapshotEntry `json:"entries"`
+}
+
+// snapshotEntry is one cached entry. StoredAt is missing from snapshots
+// written before it was added.
+type snapshotEntry struct {
+	Key       string             `json:"key"`
+	StoredAt  time.Time          `json:"stored_at,omitempty"`
+	ExpiresAt time.Time          `json:"expires_at"`
+	Data      models.WeatherData `json:"data"`
+}
+
+// SaveSnapshot writes every unexpired entry and its expiry to filename.
+// The file is written to a temporary path and renamed so a crash never
+// leaves a truncated snapshot behind.
+func (cm *CacheManager) SaveSnapshot(filename string) (int, error) {
+	expiries, err := cm.backend.Entries()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list cache entries: %v", err)
+	}
+
+	snapshot := snapshotFile{
+		Version:   snapshotVersion,
+		Schema:    weatherDataSchema(),
+		CreatedAt: time.Now(),
+		Entries:   make([]snapshotEntry, 0, len(expiries)),
+	}
+	for key := range expiries {
+		entry, found, err := cm.backend.Get(key)
+		if err != nil || !found {
+			continue
+		}
+		entry.Data.CacheHit = false
+		snapshot.Entries = append(snapshot.Entries, snapshotEntry{
+			Key:       key,
+			StoredAt:  entry.StoredAt,
+			ExpiresAt: entry.ExpiresAt,
+			Data:      entry.Data,
+		})
+	}
+
+	bytes, err := json.Marshal(snapshot)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode snapshot: %v", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".tmp-*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create snapshot file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(bytes); err != nil {
+		tmp.Close()
+		return 0, fmt.Errorf("failed to write snapshot: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, fmt.Errorf("failed to write snapshot: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		return 0, fmt.Errorf("failed to replace snapshot: %v", err)
+	}
+
+	return len(snapshot.Entries), nil
+}
+
+// LoadSnapshot restores entries from filename, skipping any that have
+// expired. A memory backend keeps each entry's original write time, or none
+// if the snapshot did not record it. A missing file is not an error. Snapshots written by a different
+// format version or WeatherData schema are ignored.
+func (cm *CacheManager) LoadSnapshot(filename string) (int, error) {
+	bytes, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, fmt.Errorf("failed to read snapshot: %v", err)
+	}
+
+	var snapshot snapshotFile
+	if err := json.Unmarshal(bytes, &snapshot); err != nil {
+		return 0, fmt.Errorf("failed to parse snapshot: %v", err)
+	}
+	if snapshot.Version != snapshotVersion {
+		return 0, fmt.Errorf("snapshot version %d is not supported (expected %d)", snapshot.Version, snapshotVersion)
+	}
+	if snapshot.Schema != weatherDataSchema() {
+		return 0, fmt.Errorf("snapshot was written for a different WeatherData schema")
+	}
+
+	restored := 0
+	now := time.Now()
+	for _, entry := range snapshot.Entries {
+		ttl := entry.ExpiresAt.Sub(now)
+		if ttl <= 0 {
+			continue
+		}
+		if memory, ok := cm.backend.(*MemoryBackend); ok {
+			memory.SetEntry(entry.Key, Entry{Data: entry.Data, StoredAt: entry.StoredAt, ExpiresAt: entry.ExpiresAt}, ttl)
+			restored++
+			continue
+		}
+		if err := cm.backend.Set(entry.Key, entry.Data, ttl); err != nil {
+			return restored, fmt.Errorf("failed to restore '%s': %v", entry.Key, err)
+		}
+		restored++
+	}
+
+	return restored, nil
+}
+
+// weatherDataSchema fingerprints the JSON shape of WeatherData so adding,
+// removing or retyping a field invalidates older snapshots
+func weatherDataSchema() string {
+	var b strings.Builder
+	describeType(&b, reflect.TypeOf(models.WeatherData{}))
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:8])
+}
+
+func describeType(b *strings.Builder, t reflect.Type) {
+	switch t.Kind() {
+	case reflect.Struct:
+		b.WriteString("{")
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			tag := field.Tag.Get("json")
+			if tag == "-" || !field.IsExported() {
+				continue
+			}
+			b.WriteString(tag)
+			b.WriteString(":")
+			describeType(b, field.Type)
+			b.WriteString(";")
+		}
+		b.WriteString("}")
+	case reflect.Slice:
+		b.WriteString("[]")
+		describeType(b, t.Elem())
+	default:
+		b.WriteString(t.Kind().String())
+	}
+}
@@ -0,0 +1,164 @@
+package cache
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/models"
+)
+
+// TieredBackend puts a short-lived in-process L1 in front of a shared L2.
+// Writes go through to both tiers; writes and deletes are broadcast so every
+// replica drops its L1 copy.
+type TieredBackend struct {
+	l1     *MemoryBackend
+	l2     Backend
+	l1TTL  time.Duration
+	origin string
+	stop   func()
+
+	l1Hits int64
+	l2Hits int64
+	misses int64
+}
+
+// NewTieredBackend creates a tiered backend. If l2 implements Invalidator,
+// invalidations from other replicas are applied to the local L1.
+func NewTieredBackend(l1 *MemoryBackend, l1TTL time.Duration, l2 Backend) (*TieredBackend, error) {
+	originBytes := make([]byte, 8)
+	rand.Read(originBytes)
+
+	tb := &TieredBackend{
+		l1:     l1,
+		l2:     l2,
+		l1TTL:  l1TTL,
+		origin: hex.EncodeToString(originBytes),
+	}
+
+	if invalidator, ok := l2.(Invalidator); ok {
+		stop, err := invalidator.SubscribeInvalidations(tb.applyInvalidation)
+		if err != nil {
+			tb.l1.Close()
+			return nil, err
+		}
+		tb.stop = stop
+	}
+
+	return tb, nil
+}
+
+// Get checks L1, then L2, promoting L2 hits into L1
+func (tb *TieredBackend) Get(key string) (Entry, bool, error) {
+	if entry, found, _ := tb.l1.Get(key); found {
+		atomic.AddInt64(&tb.l1Hits, 1)
+		return entry, true, nil
+	}
+
+	entry, found, err := tb.l2.Get(key)
+	if err != nil || !found {
+		atomic.AddInt64(&tb.misses, 1)
+		return Entry{}, false, err
+	}
+
+	atomic.AddInt64(&tb.l2Hits, 1)
+	tb.l1.SetEntry(key, entry, tb.l1TTL)
+	return entry, true, nil
+}
+
+// Set writes through to L2 and then L1, and tells other replicas to drop
+// their now stale L1 copy
+func (tb *TieredBackend) Set(key string, data models.WeatherData, ttl time.Duration) error {
+	if err := tb.l2.Set(key, data, ttl); err != nil {
+		return err
+	}
+	now := time.Now()
+	tb.l1.SetEntry(key, Entry{Data: data, StoredAt: now, ExpiresAt: now.Add(ttl)}, tb.l1TTL)
+	tb.publish(Invalidation{Key: key})
+	return nil
+}
+
+// Delete removes a key from both tiers and tells other replicas to drop it
+func (tb *TieredBackend) Delete(key string) error {
+	err := tb.l2.Delete(key)
+	tb.l1.Delete(key)
+	tb.publish(Invalidation{Key: key})
+	return err
+}
+
+// Clear empties both tiers and tells other replicas to empty their L1
+func (tb *TieredBackend) Clear() error {
+	err := tb.l2.Clear()
+	tb.l1.Clear()
+	tb.publish(Invalidation{All: true})
+	return err
+}
+
+// Entries reports the shared tier, which is authoritative
+func (tb *TieredBackend) Entries() (map[string]time.Time, error) {
+	return tb.l2.Entries()
+}
+
+// Count counts keys in the shared tier
+func (tb *TieredBackend) Count(prefix string) (int, error) {
+	return CountEntries(tb.l2, prefix)
+}
+
+// EntriesWithPrefix lists keys from the shared tier
+func (tb *TieredBackend) EntriesWithPrefix(prefix string, limit int) (map[string]time.Time, bool, error) {
+	return ListEntries(tb.l2, prefix, limit)
+}
+
+// Stats reports hits per tier and L1 evictions
+func (tb *TieredBackend) Stats() map[string]interface{} {
+	l1Stats := tb.l1.Stats()
+	return map[string]interface{}{
+		"tiers": map[string]interface{}{
+			"l1_hits":    atomic.LoadInt64(&tb.l1Hits),
+			"l2_hits":    atomic.LoadInt64(&tb.l2Hits),
+			"misses":     atomic.LoadInt64(&tb.misses),
+			"l1_entries": l1Stats["entries"],
+			"l1_ttl":     tb.l1TTL.String(),
+		},
+		"evictions": l1Stats["evictions"],
+	}
+}
+
+// Evictions returns the number of entries evicted from L1
+func (tb *TieredBackend) Evictions() int64 {
+	return tb.l1.Evictions()
+}
+
+// Close stops listening for invalidations and closes both tiers
+func (tb *TieredBackend) Close() error {
+	if tb.stop != nil {
+		tb.stop()
+	}
+	tb.l1.Close()
+	return tb.l2.Close()
+}
+
+func (tb *TieredBackend) publish(inv Invalidation) {
+	invalidator, ok := tb.l2.(Invalidator)
+	if !ok {
+		return
+	}
+	inv.Origin = tb.origin
+	if err := invalidator.PublishInvalidation(inv); err != nil {
+		log.Printf("⚠️  Failed to broadcast cache invalidation: %v", err)
+	}
+}
+
+// applyInvalidation drops L1 entries invalidated by another replica
+func (tb *TieredBackend) applyInvalidation(inv Invalidation) {
+	if inv.Origin == tb.origin {
+		return
+	}
+	if inv.All {
+		tb.l1.Clear()
+		return
+	}
+	tb.l1.Delete(inv.Key)
+}
@@ -1,76 +1,249 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/api/openweathermap"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/location"
 )
 
+// NegativeCacheOff as NegativeCacheMinutes turns off caching of "not found"
+// results
+const NegativeCacheOff = -1
+
 // Config represents the application configuration
 type Config struct {
-	OpenWeatherMapApiKey string `json:"OpenWeatherMapApiKey"`
-	CacheExpiryMinutes   int    `json:"CacheExpiryMinutes"`
-	RateLimitPerMinute   int    `json:"RateLimitPerMinute"`
-	MaxConcurrentReqs    int    `json:"MaxConcurrentRequests"`
-	ServerPort           string `json:"ServerPort"`
-	LogLevel             string `json:"LogLevel"`
+	OpenWeatherMapApiKey string         `json:"OpenWeatherMapApiKey"`
+	BaseURL              string         `json:"BaseURL"`
+	CacheExpiryMinutes   int            `json:"CacheExpiryMinutes"`
+	NegativeCacheMinutes int            `json:"NegativeCacheMinutes"`
+	UVCacheMinutes       int            `json:"UVCacheMinutes"`
+	AirQualityCacheMins  int            `json:"AirQualityCacheMinutes"`
+	RateLimitPerMinute   int            `json:"RateLimitPerMinute"`
+	MaxConcurrentReqs    int            `json:"MaxConcurrentRequests"`
+	ServerPort           string         `json:"ServerPort"`
+	LogLevel             string         `json:"LogLevel"`
+	Auth                 AuthConfig     `json:"Auth"`
+	Tenants              []TenantConfig `json:"Tenants"`
+	CORS                 CORSConfig     `json:"CORS"`
+	CacheBackend         string         `json:"CacheBackend"`
+	CacheMaxEntries      int            `json:"CacheMaxEntries"`
+	CacheMaxBytes        int64          `json:"CacheMaxBytes"`
+	CacheSnapshotFile    string         `json:"CacheSnapshotFile"`
+	Redis                RedisConfig    `json:"Redis"`
+	LocationCache        LocationConfig `json:"LocationCache"`
+}
+
+// LocationConfig represents how coordinate lookups are grouped into cache
+// entries. Strategy is "grid" (snap to GridDegrees) or "geohash" (snap to a
+// geohash cell of GeohashPrecision characters).
+type LocationConfig struct {
+	Strategy         string  `json:"Strategy" yaml:"strategy"`
+	GridDegrees      float64 `json:"GridDegrees" yaml:"grid_degrees"`
+	GeohashPrecision int     `json:"GeohashPrecision" yaml:"geohash_precision"`
 }
 
-// LoadConfig loads configuration from a JSON file
+// RedisConfig represents the connection to a shared Redis cache
+type RedisConfig struct {
+	Addr         string `json:"Addr" yaml:"addr"`
+	Password     string `json:"Password" yaml:"password"`
+	DB           int    `json:"DB" yaml:"db"`
+	KeyPrefix    string `json:"KeyPrefix" yaml:"key_prefix"`
+	L1TTLSeconds int    `json:"L1TTLSeconds" yaml:"l1_ttl_seconds"`
+	L1MaxEntries int    `json:"L1MaxEntries" yaml:"l1_max_entries"`
+}
+
+// CORSConfig represents the cross-origin resource sharing policy.
+// AllowedMethods defaults to the methods served by the registered routes.
+type CORSConfig struct {
+	AllowedOrigins   []string `json:"AllowedOrigins" yaml:"allowed_origins"`
+	AllowedMethods   []string `json:"AllowedMethods" yaml:"allowed_methods"`
+	AllowedHeaders   []string `json:"AllowedHeaders" yaml:"allowed_headers"`
+	AllowCredentials bool     `json:"AllowCredentials" yaml:"allow_credentials"`
+	MaxAgeSeconds    int      `json:"MaxAgeSeconds" yaml:"max_age_seconds"`
+}
+
+// TenantConfig represents a team sharing the service with its own upstream key and limits
+type TenantConfig struct {
+	ID                   string   `json:"ID" yaml:"id"`
+	APIKeys              []string `json:"APIKeys" yaml:"api_keys"`
+	OpenWeatherMapApiKey string   `json:"OpenWeatherMapApiKey" yaml:"openweathermap_api_key"`
+	RateLimitPerMinute   int      `json:"RateLimitPerMinute" yaml:"rate_limit_per_minute"`
+	CacheNamespace       string   `json:"CacheNamespace" yaml:"cache_namespace"`
+	AllowedEndpoints     []string `json:"AllowedEndpoints" yaml:"allowed_endpoints"`
+}
+
+// AuthConfig represents JWT bearer token authentication settings
+type AuthConfig struct {
+	Enabled  bool   `json:"Enabled" yaml:"enabled"`
+	JWKSURL  string `json:"JWKSURL" yaml:"jwks_url"`
+	KeyFile  string `json:"KeyFile" yaml:"key_file"`
+	Issuer   string `json:"Issuer" yaml:"issuer"`
+	Audience string `json:"Audience" yaml:"audience"`
+}
+
+// Keyer returns the coordinate cache key strategy described by the config
+func (l LocationConfig) Keyer() (location.Keyer, error) {
+	return location.NewKeyer(l.Strategy, l.GridDegrees, l.GeohashPrecision)
+}
+
+// LoadConfig loads configuration from a file and the environment. Files
+// ending in .yaml or .yml use the nested YAML schema; anything else is parsed
+// as flat JSON. Environment variables override the file and defaults fill in
+// whatever is left, so the file is optional when the environment provides
+// every required setting. Unknown keys are rejected and every invalid
+// setting is reported in a single *ValidationError.
 func LoadConfig(filename string) (*Config, error) {
-	// Check if file exists
-	if _, err := os.Stat(filename); os.IsNotExist(err) {
-		return nil, fmt.Errorf("config file '%s' not found. Please create it with your OpenWeatherMap API key", filename)
-	}
+	var config Config
 
-	// Read file
-	bytes, err := ioutil.ReadFile(filename)
-	if err != nil {
+	// Read file, if present
+	data, err := ioutil.ReadFile(filename)
+	fileFound := err == nil
+	if err != nil && !os.IsNotExist(err) {
 		return nil, fmt.Errorf("failed to read config file: %v", err)
 	}
 
-	// Parse JSON
-	var config Config
-	if err := json.Unmarshal(bytes, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %v", err)
+	// Parse by format
+	if fileFound {
+		if isYAML(filename) {
+			config, err = parseYAML(data)
+		} else {
+			config, err = parseJSON(data)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse config file: %v", err)
+		}
+	}
+
+	// Environment variables take precedence over the file
+	if err := config.applyEnv(); err != nil {
+		return nil, err
+	}
+
+	// Resolve file: and env: secret references
+	if err := config.resolveSecrets(); err != nil {
+		return nil, err
+	}
+
+	if config.OpenWeatherMapApiKey == "" && !fileFound {
+		return nil, fmt.Errorf("config file '%s' not found and WEATHER_API_KEY is not set. Please create it with your OpenWeatherMap API key", filename)
+	}
+
+	config.applyDefaults()
+	if err := config.Validate(); err != nil {
+		return nil, err
 	}
+	return &config, nil
+}
 
-	// Validate required fields
-	if config.OpenWeatherMapApiKey == "" {
-		return nil, fmt.Errorf("OpenWeatherMapApiKey is required in config file")
+// parseJSON decodes the flat JSON schema, rejecting unknown keys and
+// anything after the config object
+func parseJSON(data []byte) (Config, error) {
+	var config Config
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return Config{}, err
 	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return Config{}, fmt.Errorf("unexpected data after the config object at offset %d", decoder.InputOffset())
+	}
+	return config, nil
+}
 
-	// Set default values
-	if config.CacheExpiryMinutes == 0 {
-		config.CacheExpiryMinutes = 10
+// applyDefaults fills in every setting left at its zero value
+func (c *Config) applyDefaults() {
+	if c.BaseURL == "" {
+		c.BaseURL = openweathermap.DefaultBaseURL
+	}
+	if c.CacheExpiryMinutes == 0 {
+		c.CacheExpiryMinutes = 10
+	}
+	if c.NegativeCacheMinutes == 0 {
+		c.NegativeCacheMinutes = 2
+	}
+	if c.UVCacheMinutes == 0 {
+		c.UVCacheMinutes = 60
+	}
+	if c.AirQualityCacheMins == 0 {
+		c.AirQualityCacheMins = 30
+	}
+	if c.RateLimitPerMinute == 0 {
+		c.RateLimitPerMinute = 100
+	}
+	if c.MaxConcurrentReqs == 0 {
+		c.MaxConcurrentReqs = 50
+	}
+	if c.ServerPort == "" {
+		c.ServerPort = "8080"
 	}
-	if config.RateLimitPerMinute == 0 {
-		config.RateLimitPerMinute = 100
+	if c.LogLevel == "" {
+		c.LogLevel = "info"
 	}
-	if config.MaxConcurrentReqs == 0 {
-		config.MaxConcurrentReqs = 50
+	if c.CacheBackend == "" {
+		c.CacheBackend = "memory"
 	}
-	if config.ServerPort == "" {
-		config.ServerPort = "8080"
+	if c.CacheBackend == "redis" {
+		if c.Redis.L1TTLSeconds == 0 {
+			c.Redis.L1TTLSeconds = 30
+		}
+		if c.Redis.L1MaxEntries == 0 {
+			c.Redis.L1MaxEntries = 1000
+		}
 	}
-	if config.LogLevel == "" {
-		config.LogLevel = "info"
+	if c.CacheMaxEntries == 0 {
+		c.CacheMaxEntries = 10000
+	}
+	if c.CacheMaxBytes == 0 {
+		c.CacheMaxBytes = 64 << 20
+	}
+	if c.CORS.AllowedOrigins == nil {
+		c.CORS.AllowedOrigins = []string{"http://localhost:3000"}
+	}
+	if c.CORS.AllowedHeaders == nil {
+		c.CORS.AllowedHeaders = []string{"Content-Type", "Authorization", "X-API-Key"}
+	}
+	if c.CORS.MaxAgeSeconds == 0 {
+		c.CORS.MaxAgeSeconds = 3600
 	}
 
-	return &config, nil
+	if c.LocationCache.Strategy == "" {
+		c.LocationCache.Strategy = location.StrategyGrid
+	}
+	if c.LocationCache.GridDegrees == 0 {
+		c.LocationCache.GridDegrees = location.DefaultGridDegrees
+	}
+	if c.LocationCache.GeohashPrecision == 0 {
+		c.LocationCache.GeohashPrecision = location.DefaultGeohashPrecision
+	}
+
+	for i := range c.Tenants {
+		tenant := &c.Tenants[i]
+		if tenant.OpenWeatherMapApiKey == "" {
+			tenant.OpenWeatherMapApiKey = c.OpenWeatherMapApiKey
+		}
+		if tenant.CacheNamespace == "" {
+			tenant.CacheNamespace = tenant.ID
+		}
+	}
 }
 
-// SaveExampleConfig creates an example configuration file
+// SaveExampleConfig creates an example JSON configuration file listing every
+// setting with its default value
 func SaveExampleConfig(filename string) error {
+	if isYAML(filename) {
+		return fmt.Errorf("example config is JSON; choose a file name without a .yaml or .yml extension")
+	}
+
 	exampleConfig := Config{
 		OpenWeatherMapApiKey: "your_api_key_here",
-		CacheExpiryMinutes:   10,
-		RateLimitPerMinute:   100,
-		MaxConcurrentReqs:    50,
-		ServerPort:           "8080",
-		LogLevel:             "info",
 	}
+	exampleConfig.applyDefaults()
 
 	bytes, err := json.MarshalIndent(exampleConfig, "", "  ")
 	if err != nil {
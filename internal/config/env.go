@@ -0,0 +1,137 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// envBinding maps environment variables onto one Config field. The first
+// name that is set wins; later names are aliases used by existing deployments.
+type envBinding struct {
+	names  []string
+	target interface{}
+}
+
+// envBindings lists the environment variable for every config field
+func (c *Config) envBindings() []envBinding {
+	return []envBinding{
+		{[]string{"WEATHER_API_KEY", "OPENWEATHERMAP_API_KEY", "OPENWEATHER_API_KEY"}, &c.OpenWeatherMapApiKey},
+		{[]string{"WEATHER_API_BASE_URL"}, &c.BaseURL},
+		{[]string{"CACHE_EXPIRY_MINUTES"}, &c.CacheExpiryMinutes},
+		{[]string{"NEGATIVE_CACHE_MINUTES"}, &c.NegativeCacheMinutes},
+		{[]string{"UV_CACHE_MINUTES"}, &c.UVCacheMinutes},
+		{[]string{"AIR_QUALITY_CACHE_MINUTES"}, &c.AirQualityCacheMins},
+		{[]string{"RATE_LIMIT_PER_MINUTE"}, &c.RateLimitPerMinute},
+		{[]string{"MAX_CONCURRENT_REQUESTS"}, &c.MaxConcurrentReqs},
+		{[]string{"SERVER_PORT", "PORT"}, &c.ServerPort},
+		{[]string{"LOG_LEVEL"}, &c.LogLevel},
+		{[]string{"AUTH_ENABLED"}, &c.Auth.Enabled},
+		{[]string{"AUTH_JWKS_URL"}, &c.Auth.JWKSURL},
+		{[]string{"AUTH_KEY_FILE"}, &c.Auth.KeyFile},
+		{[]string{"AUTH_ISSUER"}, &c.Auth.Issuer},
+		{[]string{"AUTH_AUDIENCE"}, &c.Auth.Audience},
+		{[]string{"TENANTS"}, &c.Tenants},
+		{[]string{"CORS_ALLOWED_ORIGINS"}, &c.CORS.AllowedOrigins},
+		{[]string{"CORS_ALLOWED_METHODS"}, &c.CORS.AllowedMethods},
+		{[]string{"CORS_ALLOWED_HEADERS"}, &c.CORS.AllowedHeaders},
+		{[]string{"CORS_ALLOW_CREDENTIALS"}, &c.CORS.AllowCredentials},
+		{[]string{"CORS_MAX_AGE_SECONDS"}, &c.CORS.MaxAgeSeconds},
+		{[]string{"CACHE_BACKEND"}, &c.CacheBackend},
+		{[]string{"CACHE_MAX_ENTRIES"}, &c.CacheMaxEntries},
+		{[]string{"CACHE_MAX_BYTES"}, &c.CacheMaxBytes},
+		{[]string{"CACHE_SNAPSHOT_FILE"}, &c.CacheSnapshotFile},
+		{[]string{"REDIS_ADDR"}, &c.Redis.Addr},
+		{[]string{"REDIS_PASSWORD"}, &c.Redis.Password},
+		{[]string{"REDIS_DB"}, &c.Redis.DB},
+		{[]string{"REDIS_KEY_PREFIX"}, &c.Redis.KeyPrefix},
+		{[]string{"REDIS_L1_TTL_SECONDS"}, &c.Redis.L1TTLSeconds},
+		{[]string{"REDIS_L1_MAX_ENTRIES"}, &c.Redis.L1MaxEntries},
+		{[]string{"LOCATION_CACHE_STRATEGY"}, &c.LocationCache.Strategy},
+		{[]string{"LOCATION_CACHE_GRID_DEGREES"}, &c.LocationCache.GridDegrees},
+		{[]string{"LOCATION_CACHE_GEOHASH_PRECISION"}, &c.LocationCache.GeohashPrecision},
+	}
+}
+
+// EnvVars returns the primary environment variable name for every field
+func EnvVars() []string {
+	var c Config
+	bindings := c.envBindings()
+	names := make([]string, len(bindings))
+	for i, binding := range bindings {
+		names[i] = binding.names[0]
+	}
+	return names
+}
+
+// applyEnv overrides config fields with any environment variables that are
+// set. Lists are comma separated; TENANTS holds a JSON array.
+func (c *Config) applyEnv() error {
+	for _, binding := range c.envBindings() {
+		for _, name := range binding.names {
+			value, ok := os.LookupEnv(name)
+			if !ok {
+				continue
+			}
+			if err := setFromEnv(binding.target, value); err != nil {
+				return fmt.Errorf("invalid value for %s: %v", name, err)
+			}
+			break
+		}
+	}
+	return nil
+}
+
+func setFromEnv(target interface{}, value string) error {
+	value = strings.TrimSpace(value)
+	switch field := target.(type) {
+	case *string:
+		*field = value
+	case *int:
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("expected an integer, got '%s'", value)
+		}
+		*field = parsed
+	case *int64:
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("expected an integer, got '%s'", value)
+		}
+		*field = parsed
+	case *float64:
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("expected a number, got '%s'", value)
+		}
+		*field = parsed
+	case *bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("expected true or false, got '%s'", value)
+		}
+		*field = parsed
+	case *[]string:
+		*field = splitList(value)
+	case *[]TenantConfig:
+		if err := json.Unmarshal([]byte(value), field); err != nil {
+			return fmt.Errorf("expected a JSON array of tenants: %v", err)
+		}
+	default:
+		return fmt.Errorf("unsupported field type %T", target)
+	}
+	return nil
+}
+
+// splitList splits a comma separated list, dropping empty items
+func splitList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,211 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// restartRequired lists settings that are only read at startup. Changes to
+// them are reported by a reload but take effect after a restart, so the
+// active config keeps their startup values until then.
+var restartRequired = map[string]bool{
+	"ServerPort":            true,
+	"MaxConcurrentRequests": true,
+	"Auth":                  true,
+	"CacheBackend":          true,
+	"CacheMaxEntries":       true,
+	"CacheMaxBytes":         true,
+	"CacheSnapshotFile":     true,
+	"Redis":                 true,
+	"LocationCache":         true,
+}
+
+// Watcher reloads the config file on SIGHUP or when the file changes. A
+// reload that fails to load or validate, or finds the file missing, is
+// rejected and the previous config stays active.
+type Watcher struct {
+	filename string
+	apply    func(old, updated *Config)
+	current  atomic.Pointer[Config]
+	mu       sync.Mutex
+	modTime  time.Time
+	size     int64
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewWatcher creates a watcher for filename starting from the loaded config.
+// apply is called with the old and new config after every accepted reload.
+func NewWatcher(filename string, initial *Config, apply func(old, updated *Config)) *Watcher {
+	w := &Watcher{
+		filename: filename,
+		apply:    apply,
+		stop:     make(chan struct{}),
+	}
+	w.current.Store(initial)
+	w.modTime, w.size = fileVersion(filename)
+	return w
+}
+
+// Current returns the active config
+func (w *Watcher) Current() *Config {
+	return w.current.Load()
+}
+
+// Reload loads the file and environment again and applies the result. It
+// returns the changed settings, or an error if the new config was rejected.
+func (w *Watcher) Reload() ([]string, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.modTime, w.size = fileVersion(w.filename)
+	// LoadConfig accepts a missing file when the environment is complete, but
+	// a file removed while running must not reset every setting to defaults
+	if _, err := os.Stat(w.filename); err != nil {
+		return nil, fmt.Errorf("config file '%s' cannot be read: %v", w.filename, err)
+	}
+	updated, err := LoadConfig(w.filename)
+	if err != nil {
+		return nil, err
+	}
+
+	old := w.current.Load()
+	changes := Diff(old, updated)
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	updated = withStartupSettings(old, updated)
+	w.apply(old, updated)
+	w.current.Store(updated)
+	return changes, nil
+}
+
+// withStartupSettings returns a copy of updated that keeps the values of old
+// for every setting in restartRequired, since those are still in effect
+func withStartupSettings(old, updated *Config) *Config {
+	merged := *updated
+	oldValue, mergedValue := reflect.ValueOf(old).Elem(), reflect.ValueOf(&merged).Elem()
+	for i := 0; i < mergedValue.NumField(); i++ {
+		if restartRequired[jsonName(mergedValue.Type().Field(i))] {
+			mergedValue.Field(i).Set(oldValue.Field(i))
+		}
+	}
+	return &merged
+}
+
+// Start watches for SIGHUP and polls the file for changes every interval
+func (w *Watcher) Start(interval time.Duration) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		defer signal.Stop(signals)
+
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-signals:
+				log.Println("🔄 SIGHUP received, reloading configuration...")
+				w.reloadAndLog()
+			case <-ticker.C:
+				if modTime, size := fileVersion(w.filename); !modTime.Equal(w.modTime) || size != w.size {
+					log.Printf("🔄 %s changed, reloading configuration...", w.filename)
+					w.reloadAndLog()
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops watching
+func (w *Watcher) Stop() {
+	w.stopOnce.Do(func() { close(w.stop) })
+}
+
+func (w *Watcher) reloadAndLog() {
+	changes, err := w.Reload()
+	if err != nil {
+		log.Printf("⚠️  Rejected config reload, keeping previous config: %v", err)
+		return
+	}
+	if len(changes) == 0 {
+		log.Println("✅ Configuration reloaded, nothing changed")
+		return
+	}
+	log.Printf("✅ Configuration reloaded with %d change(s):", len(changes))
+	for _, change := range changes {
+		log.Printf("   %s", change)
+	}
+}
+
+// fileVersion returns the modification time and size of filename, or zero
+// values when it does not exist
+func fileVersion(filename string) (time.Time, int64) {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return time.Time{}, 0
+	}
+	return info.ModTime(), info.Size()
+}
+
+// Diff describes every setting that differs between old and updated, one
+// line per setting such as "CacheExpiryMinutes: 10 -> 5". Secrets are
+// redacted and settings that need a restart are marked.
+func Diff(old, updated *Config) []string {
+	var changes []string
+	diffValues("", reflect.ValueOf(*old.Redacted()), reflect.ValueOf(*updated.Redacted()), false, &changes)
+
+	// Redacted secrets compare equal, so check the real values separately
+	oldSecrets, updatedSecrets := old.secretFields(), updated.secretFields()
+	var secretChanges []string
+	for name, field := range updatedSecrets {
+		if previous, ok := oldSecrets[name]; ok && *previous != *field && *previous != "" && *field != "" {
+			secretChanges = append(secretChanges, fmt.Sprintf("%s: changed", name))
+		}
+	}
+	sort.Strings(secretChanges)
+	return append(changes, secretChanges...)
+}
+
+func diffValues(path string, old, updated reflect.Value, restart bool, changes *[]string) {
+	if old.Kind() == reflect.Struct {
+		for i := 0; i < old.NumField(); i++ {
+			name := jsonName(old.Type().Field(i))
+			fieldPath := name
+			if path != "" {
+				fieldPath = path + "." + name
+			}
+			diffValues(fieldPath, old.Field(i), updated.Field(i), restart || restartRequired[fieldPath], changes)
+		}
+		return
+	}
+
+	if reflect.DeepEqual(old.Interface(), updated.Interface()) {
+		return
+	}
+	change := fmt.Sprintf("%s: %v -> %v", path, old.Interface(), updated.Interface())
+	if restart {
+		change += " (requires restart)"
+	}
+	*changes = append(*changes, change)
+}
+
+// jsonName returns the JSON key of a config field
+func jsonName(field reflect.StructField) string {
+	if tag := field.Tag.Get("json"); tag != "" {
+		return tag
+	}
+	return field.Name
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// redacted replaces secret values in logs and config dumps
+const redacted = "[REDACTED]"
+
+// secretFields returns a pointer to every secret in the config along with a
+// name for error messages
+func (c *Config) secretFields() map[string]*string {
+	fields := map[string]*string{
+		"OpenWeatherMapApiKey": &c.OpenWeatherMapApiKey,
+		"Redis.Password":       &c.Redis.Password,
+	}
+	for i := range c.Tenants {
+		tenant := &c.Tenants[i]
+		fields[fmt.Sprintf("Tenants[%d].OpenWeatherMapApiKey", i)] = &tenant.OpenWeatherMapApiKey
+		for j := range tenant.APIKeys {
+			fields[fmt.Sprintf("Tenants[%d].APIKeys[%d]", i, j)] = &tenant.APIKeys[j]
+		}
+	}
+	return fields
+}
+
+// resolveSecrets replaces secret references with their values:
+// "file:/run/secrets/owm_key" reads the file (trimming surrounding
+// whitespace) and "env:OWM_KEY" reads the environment variable. Any other
+// value is used as is.
+func (c *Config) resolveSecrets() error {
+	for name, field := range c.secretFields() {
+		value, err := resolveSecret(*field)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s: %v", name, err)
+		}
+		*field = value
+	}
+	return nil
+}
+
+func resolveSecret(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, "file:"):
+		filename := strings.TrimPrefix(value, "file:")
+		bytes, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return "", fmt.Errorf("secret file '%s' could not be read: %v", filename, err)
+		}
+		secret := strings.TrimSpace(string(bytes))
+		if secret == "" {
+			return "", fmt.Errorf("secret file '%s' is empty", filename)
+		}
+		return secret, nil
+	case strings.HasPrefix(value, "env:"):
+		name := strings.TrimPrefix(value, "env:")
+		secret, ok := os.LookupEnv(name)
+		if !ok || secret == "" {
+			return "", fmt.Errorf("secret environment variable '%s' is not set", name)
+		}
+		return secret, nil
+	default:
+		return value, nil
+	}
+}
+
+// Redacted returns a copy of the config that is safe to log or serve, with
+// every secret replaced by a placeholder
+func (c *Config) Redacted() *Config {
+	clone := *c
+	if c.Tenants != nil {
+		clone.Tenants = make([]TenantConfig, len(c.Tenants))
+		for i, tenant := range c.Tenants {
+			tenant.APIKeys = append([]string(nil), tenant.APIKeys...)
+			clone.Tenants[i] = tenant
+		}
+	}
+
+	for _, field := range clone.secretFields() {
+		if *field != "" {
+			*field = redacted
+		}
+	}
+	return &clone
+}
+
+// String renders the config as JSON with secrets redacted, so printing a
+// Config with %v or %+v never leaks a key
+func (c Config) String() string {
+	bytes, err := json.Marshal(c.Redacted())
+	if err != nil {
+		return fmt.Sprintf("config: %v", err)
+	}
+	return string(bytes)
+}
+
+// GoString keeps %#v redacted as well
+func (c Config) GoString() string {
+	return c.String()
+}
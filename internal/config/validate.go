@@ -0,0 +1,163 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/logging"
+)
+
+// Limits accepted by Validate
+const (
+	maxCacheMinutes       = 24 * 60
+	maxRateLimitPerMinute = 1000000
+	maxConcurrentRequests = 10000
+)
+
+// ValidationError lists every problem found in a config
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid config: %s", strings.Join(e.Problems, "; "))
+}
+
+// Validate checks every setting and reports all problems at once. It expects
+// defaults to have been applied, so zero values are treated as errors.
+func (c *Config) Validate() error {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+	checkRange := func(name string, value, min, max int) {
+		if value < min || value > max {
+			add("%s must be between %d and %d, got %d", name, min, max, value)
+		}
+	}
+
+	if c.OpenWeatherMapApiKey == "" {
+		add("OpenWeatherMapApiKey is required in config file or WEATHER_API_KEY")
+	}
+	if err := validateURL(c.BaseURL); err != nil {
+		add("invalid BaseURL '%s': %v", c.BaseURL, err)
+	}
+
+	checkRange("CacheExpiryMinutes", c.CacheExpiryMinutes, 1, maxCacheMinutes)
+	if c.NegativeCacheMinutes != NegativeCacheOff {
+		checkRange("NegativeCacheMinutes", c.NegativeCacheMinutes, 1, maxCacheMinutes)
+	}
+	checkRange("UVCacheMinutes", c.UVCacheMinutes, 1, maxCacheMinutes)
+	checkRange("AirQualityCacheMinutes", c.AirQualityCacheMins, 1, maxCacheMinutes)
+	checkRange("RateLimitPerMinute", c.RateLimitPerMinute, 1, maxRateLimitPerMinute)
+	checkRange("MaxConcurrentRequests", c.MaxConcurrentReqs, 1, maxConcurrentRequests)
+
+	if port, err := strconv.Atoi(c.ServerPort); err != nil || port < 1 || port > 65535 {
+		add("ServerPort must be a number between 1 and 65535, got '%s'", c.ServerPort)
+	}
+	if _, err := logging.ParseLevel(c.LogLevel); err != nil {
+		add("invalid LogLevel: %v", err)
+	}
+
+	if c.Auth.Enabled && c.Auth.JWKSURL == "" && c.Auth.KeyFile == "" {
+		add("Auth requires either JWKSURL or KeyFile when enabled")
+	}
+	if c.Auth.Enabled && c.Auth.Issuer == "" {
+		add("Auth.Issuer is required when Auth is enabled")
+	}
+	if c.Auth.Enabled && c.Auth.Audience == "" {
+		add("Auth.Audience is required when Auth is enabled")
+	}
+	if c.Auth.JWKSURL != "" {
+		if err := validateURL(c.Auth.JWKSURL); err != nil {
+			add("invalid Auth.JWKSURL '%s': %v", c.Auth.JWKSURL, err)
+		}
+	}
+
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin == "*" {
+			if c.CORS.AllowCredentials {
+				add("CORS.AllowedOrigins must list origins explicitly when CORS.AllowCredentials is set, not '*'")
+			}
+			continue
+		}
+		if err := validateURL(origin); err != nil {
+			add("invalid CORS.AllowedOrigins entry '%s': %v", origin, err)
+		}
+	}
+	if c.CORS.MaxAgeSeconds < 0 {
+		add("CORS.MaxAgeSeconds must not be negative, got %d", c.CORS.MaxAgeSeconds)
+	}
+
+	switch c.CacheBackend {
+	case "memory":
+	case "redis":
+		if c.Redis.Addr == "" {
+			add("Redis.Addr is required when CacheBackend is 'redis'")
+		}
+	default:
+		add("unknown CacheBackend '%s' (expected 'memory' or 'redis')", c.CacheBackend)
+	}
+	if c.CacheMaxEntries < 1 {
+		add("CacheMaxEntries must be positive, got %d", c.CacheMaxEntries)
+	}
+	if c.CacheMaxBytes < 1 {
+		add("CacheMaxBytes must be positive, got %d", c.CacheMaxBytes)
+	}
+	if c.Redis.DB < 0 {
+		add("Redis.DB must not be negative, got %d", c.Redis.DB)
+	}
+	if c.Redis.L1TTLSeconds < 0 {
+		add("Redis.L1TTLSeconds must not be negative, got %d", c.Redis.L1TTLSeconds)
+	}
+	if c.Redis.L1MaxEntries < 0 {
+		add("Redis.L1MaxEntries must not be negative, got %d", c.Redis.L1MaxEntries)
+	}
+
+	if _, err := c.LocationCache.Keyer(); err != nil {
+		add("invalid LocationCache: %v", err)
+	}
+
+	tenantIDs := make(map[string]bool)
+	tenantKeys := make(map[string]bool)
+	for i, tenant := range c.Tenants {
+		if tenant.ID == "" {
+			add("Tenants[%d] is missing an ID", i)
+		} else if tenantIDs[tenant.ID] {
+			add("duplicate tenant ID '%s'", tenant.ID)
+		}
+		tenantIDs[tenant.ID] = true
+
+		for _, key := range tenant.APIKeys {
+			if tenantKeys[key] {
+				add("tenant '%s' reuses an API key assigned to another tenant", tenant.ID)
+			}
+			tenantKeys[key] = true
+		}
+		if tenant.RateLimitPerMinute < 0 {
+			add("tenant '%s' RateLimitPerMinute must not be negative, got %d", tenant.ID, tenant.RateLimitPerMinute)
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// validateURL checks that raw is an absolute http or https URL
+func validateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("scheme must be http or https")
+	}
+	if u.Host == "" {
+		return fmt.Errorf("missing host")
+	}
+	return nil
+}
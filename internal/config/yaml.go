@@ -0,0 +1,93 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlConfig is the nested schema of configs/config.yaml. Sections map onto
+// the flat Config in toConfig; nested blocks reuse the Config types directly.
+type yamlConfig struct {
+	WeatherAPI struct {
+		APIKey                string `yaml:"api_key"`
+		BaseURL               string `yaml:"base_url"`
+		CacheExpiryMinutes    int    `yaml:"cache_expiry_minutes"`
+		RateLimitPerMinute    int    `yaml:"rate_limit_per_minute"`
+		MaxConcurrentRequests int    `yaml:"max_concurrent_requests"`
+	} `yaml:"weather_api"`
+	Server struct {
+		Port string     `yaml:"port"`
+		CORS CORSConfig `yaml:"cors"`
+	} `yaml:"server"`
+	Log struct {
+		Level string `yaml:"level"`
+	} `yaml:"log"`
+	Cache struct {
+		Backend           string         `yaml:"backend"`
+		MaxEntries        int            `yaml:"max_entries"`
+		MaxBytes          int64          `yaml:"max_bytes"`
+		SnapshotFile      string         `yaml:"snapshot_file"`
+		NegativeMinutes   int            `yaml:"negative_minutes"`
+		UVMinutes         int            `yaml:"uv_minutes"`
+		AirQualityMinutes int            `yaml:"air_quality_minutes"`
+		Redis             RedisConfig    `yaml:"redis"`
+		Location          LocationConfig `yaml:"location"`
+	} `yaml:"cache"`
+	Auth    AuthConfig     `yaml:"auth"`
+	Tenants []TenantConfig `yaml:"tenants"`
+}
+
+// isYAML reports whether filename should be parsed as YAML
+func isYAML(filename string) bool {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// parseYAML decodes the nested YAML schema into a Config, rejecting unknown keys
+func parseYAML(data []byte) (Config, error) {
+	var file yamlConfig
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && err != io.EOF {
+		return Config{}, err
+	}
+	if err := decoder.Decode(&struct{}{}); err != io.EOF {
+		return Config{}, fmt.Errorf("unexpected second YAML document")
+	}
+	return file.toConfig(), nil
+}
+
+func (y yamlConfig) toConfig() Config {
+	// Older files point base_url at the /weather endpoint itself
+	baseURL := strings.TrimSuffix(strings.TrimRight(y.WeatherAPI.BaseURL, "/"), "/weather")
+
+	return Config{
+		OpenWeatherMapApiKey: y.WeatherAPI.APIKey,
+		BaseURL:              baseURL,
+		CacheExpiryMinutes:   y.WeatherAPI.CacheExpiryMinutes,
+		NegativeCacheMinutes: y.Cache.NegativeMinutes,
+		UVCacheMinutes:       y.Cache.UVMinutes,
+		AirQualityCacheMins:  y.Cache.AirQualityMinutes,
+		RateLimitPerMinute:   y.WeatherAPI.RateLimitPerMinute,
+		MaxConcurrentReqs:    y.WeatherAPI.MaxConcurrentRequests,
+		ServerPort:           y.Server.Port,
+		LogLevel:             y.Log.Level,
+		Auth:                 y.Auth,
+		Tenants:              y.Tenants,
+		CORS:                 y.Server.CORS,
+		CacheBackend:         y.Cache.Backend,
+		CacheMaxEntries:      y.Cache.MaxEntries,
+		CacheMaxBytes:        y.Cache.MaxBytes,
+		CacheSnapshotFile:    y.Cache.SnapshotFile,
+		Redis:                y.Cache.Redis,
+		LocationCache:        y.Cache.Location,
+	}
+}
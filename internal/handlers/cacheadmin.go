@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/api/openweathermap"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/services"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/tenant"
+	"github.com/gorilla/mux"
+)
+
+// CacheEntryHandler returns a single cache entry with its expiry and age
+func (h *Handler) CacheEntryHandler(w http.ResponseWriter, r *http.Request) {
+	key := mux.Vars(r)["key"]
+	if !h.inNamespace(w, r, key) {
+		return
+	}
+	now := time.Now()
+
+	entry, found := h.cacheManager.Peek(key)
+	if !found {
+		if h.cacheManager.PeekNotFound(key) {
+			h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+				"key":      key,
+				"negative": true,
+			})
+			return
+		}
+		h.respondWithError(w, http.StatusNotFound, "Cache entry '"+key+"' not found")
+		return
+	}
+
+	response := map[string]interface{}{
+		"key":                key,
+		"negative":           false,
+		"expires_at":         entry.ExpiresAt.Format(time.RFC3339),
+		"expires_in_seconds": int(entry.ExpiresAt.Sub(now).Seconds()),
+		"data":               entry.Data,
+	}
+	if !entry.StoredAt.IsZero() {
+		response["stored_at"] = entry.StoredAt.Format(time.RFC3339)
+		response["age_seconds"] = int(now.Sub(entry.StoredAt).Seconds())
+	}
+	h.respondWithJSON(w, http.StatusOK, response)
+}
+
+// CacheDeleteHandler removes a single cache entry
+func (h *Handler) CacheDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	key := mux.Vars(r)["key"]
+	if !h.inNamespace(w, r, key) {
+		return
+	}
+	if _, found := h.cacheManager.Peek(key); !found && !h.cacheManager.PeekNotFound(key) {
+		h.respondWithError(w, http.StatusNotFound, "Cache entry '"+key+"' not found")
+		return
+	}
+
+	if err := h.cacheManager.Delete(key); err != nil {
+		h.respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "success",
+		"message": "Cache entry deleted successfully",
+		"key":     key,
+		"time":    time.Now().Format(time.RFC3339),
+	})
+}
+
+// CacheRefreshHandler drops a cache entry and fetches it again from upstream
+func (h *Handler) CacheRefreshHandler(w http.ResponseWriter, r *http.Request) {
+	key := mux.Vars(r)["key"]
+	t, _ := tenant.FromContext(r.Context())
+
+	data, err := h.weatherService.RefreshCacheEntry(t, key)
+	if err != nil {
+		var notFound *openweathermap.NotFoundError
+		switch {
+		case errors.Is(err, services.ErrInvalidCacheKey):
+			h.respondWithError(w, http.StatusBadRequest, err.Error())
+		case errors.As(err, &notFound):
+			h.respondWithError(w, http.StatusNotFound, err.Error())
+		default:
+			h.respondWithError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"status":     "success",
+		"key":        key,
+		"expires_at": data.ExpiresAt.Format(time.RFC3339),
+		"data":       data,
+	})
+}
+
+// CacheInvalidateHandler removes every entry matching ?prefix= or ?pattern=
+// (a glob such as "acme:*"). A tenant's prefix must lie within its cache
+// namespace and its pattern only matches keys there.
+func (h *Handler) CacheInvalidateHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	prefix, pattern := query.Get("prefix"), query.Get("pattern")
+	t, _ := tenant.FromContext(r.Context())
+
+	var removed int
+	var err error
+	switch {
+	case prefix != "" && pattern != "":
+		h.respondWithError(w, http.StatusBadRequest, "Use either prefix or pattern, not both")
+		return
+	case prefix != "":
+		if !h.inNamespace(w, r, prefix) {
+			return
+		}
+		removed, err = h.cacheManager.InvalidatePrefix(prefix)
+	case pattern != "":
+		removed, err = h.cacheManager.InvalidatePatternIn(t.CacheKey(""), pattern)
+		if err != nil {
+			h.respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	default:
+		h.respondWithError(w, http.StatusBadRequest, "prefix or pattern parameter is required. Usage: DELETE /v1/cache?prefix=acme: or DELETE /v1/cache?pattern=uv:*")
+		return
+	}
+	if err != nil {
+		h.respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "success",
+		"deleted": removed,
+		"time":    time.Now().Format(time.RFC3339),
+	})
+}
+
+// inNamespace reports whether key lies within the cache namespace of the
+// request's tenant, answering 403 when it does not. Requests without a tenant
+// namespace may reach every key.
+func (h *Handler) inNamespace(w http.ResponseWriter, r *http.Request, key string) bool {
+	t, _ := tenant.FromContext(r.Context())
+	namespace := t.CacheKey("")
+	if strings.HasPrefix(key, namespace) {
+		return true
+	}
+	h.respondWithError(w, http.StatusForbidden, "Cache key '"+key+"' is outside the tenant's cache namespace '"+namespace+"'")
+	return false
+}
\ No newline at end of file
@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/config"
+)
+
+// SetConfigSource sets where ConfigHandler reads the active configuration
+func (h *Handler) SetConfigSource(source func() *config.Config) {
+	h.configSource = source
+}
+
+// ConfigHandler returns the active configuration with secrets redacted
+func (h *Handler) ConfigHandler(w http.ResponseWriter, r *http.Request) {
+	if h.configSource == nil {
+		h.respondWithError(w, http.StatusNotFound, "Configuration is not available")
+		return
+	}
+	h.respondWithJSON(w, http.StatusOK, h.configSource().Redacted())
+}
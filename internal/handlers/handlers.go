@@ -2,13 +2,20 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"time"
+	"github.com/Vivek-Prakash1307/weather-Microservices/api/openweathermap"
 	"github.com/Vivek-Prakash1307/weather-Microservices/internal/cache"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/config"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/location"
 	"github.com/Vivek-Prakash1307/weather-Microservices/internal/metrics"
 	"github.com/Vivek-Prakash1307/weather-Microservices/internal/models"
 	"github.com/Vivek-Prakash1307/weather-Microservices/internal/services"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/tenant"
 )
 
 // Handler contains all HTTP handlers
@@ -16,6 +23,7 @@ type Handler struct {
 	weatherService *services.WeatherService
 	metricsManager *metrics.MetricsManager
 	cacheManager   *cache.CacheManager
+	configSource   func() *config.Config
 }
 
 // NewHandler creates a new handler
@@ -31,21 +39,73 @@ func NewHandler(
 	}
 }
 
-// WeatherHandler handles weather requests
+// WeatherHandler handles weather requests by city or by lat/lon. units and
+// lang select the upstream unit system and description language, and
+// single_unit=true leaves out the temperatures of the other unit systems.
 func (h *Handler) WeatherHandler(w http.ResponseWriter, r *http.Request) {
-	city := r.URL.Query().Get("city")
-	if city == "" {
-		h.respondWithError(w, http.StatusBadRequest, "City parameter is required. Usage: /weather?city=CityName")
+	query := r.URL.Query()
+	city := query.Get("city")
+	t, _ := tenant.FromContext(r.Context())
+
+	var opts openweathermap.Options
+	var err error
+	if opts.Units, err = openweathermap.ParseUnits(query.Get("units")); err != nil {
+		h.respondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	if opts.Lang, err = openweathermap.ParseLang(query.Get("lang")); err != nil {
+		h.respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	singleUnit := false
+	if value := query.Get("single_unit"); value != "" {
+		if singleUnit, err = strconv.ParseBool(value); err != nil {
+			h.respondWithError(w, http.StatusBadRequest, "single_unit must be true or false")
+			return
+		}
+	}
 
-	data, err := h.weatherService.GetWeatherData(city)
+	var data *models.WeatherData
+	switch {
+	case city != "":
+		data, err = h.weatherService.GetWeatherDataForTenant(t, city, opts)
+	case query.Get("lat") != "" || query.Get("lon") != "":
+		lat, latErr := strconv.ParseFloat(query.Get("lat"), 64)
+		lon, lonErr := strconv.ParseFloat(query.Get("lon"), 64)
+		if latErr != nil || lonErr != nil || !location.ValidCoordinates(lat, lon) {
+			h.respondWithError(w, http.StatusBadRequest, "lat and lon must both be valid coordinates. Usage: /v1/weather?lat=51.51&lon=-0.13")
+			return
+		}
+		city = fmt.Sprintf("%v,%v", lat, lon)
+		data, err = h.weatherService.GetWeatherDataByCoordsForTenant(t, lat, lon, opts)
+	default:
+		h.respondWithError(w, http.StatusBadRequest, "City parameter is required. Usage: /v1/weather?city=CityName or /v1/weather?lat=51.51&lon=-0.13")
+		return
+	}
 	if err != nil {
+		var notFound *openweathermap.NotFoundError
+		if errors.As(err, &notFound) {
+			h.respondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
 		log.Printf("❌ Error fetching weather for '%s': %v", city, err)
 		h.respondWithError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	variant := ""
+	if singleUnit {
+		variant = string(opts.Units)
+	}
+	if setCacheHeaders(w, r, data, variant) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	if singleUnit {
+		h.respondWithJSON(w, http.StatusOK, services.SingleUnit(data, opts.Units))
+		return
+	}
 	h.respondWithJSON(w, http.StatusOK, data)
 }
 
@@ -77,234 +137,48 @@ func (h *Handler) ReadinessHandler(w http.ResponseWriter, r *http.Request) {
 // MetricsHandler handles metrics requests
 func (h *Handler) MetricsHandler(w http.ResponseWriter, r *http.Request) {
 	metrics := h.metricsManager.GetMetrics()
+	metrics["cache_evictions"] = h.cacheManager.Evictions()
 	h.respondWithJSON(w, http.StatusOK, metrics)
 }
 
-// CacheHandler handles cache status requests
+// CacheHandler handles cache status requests. A tenant with a cache
+// namespace only sees its own entries.
 func (h *Handler) CacheHandler(w http.ResponseWriter, r *http.Request) {
-	cacheStats := h.cacheManager.GetStats()
+	t, _ := tenant.FromContext(r.Context())
+	cacheStats := h.cacheManager.GetStatsIn(t.CacheKey(""))
 	h.respondWithJSON(w, http.StatusOK, cacheStats)
 }
 
-// CacheClearHandler handles cache clear requests
+// CacheClearHandler handles cache clear requests. ?type=negative clears
+// only cached "not found" results. A tenant with a cache namespace only
+// clears its own entries.
 func (h *Handler) CacheClearHandler(w http.ResponseWriter, r *http.Request) {
-	h.cacheManager.Clear()
+	t, _ := tenant.FromContext(r.Context())
+	namespace := t.CacheKey("")
+	message := "Cache cleared successfully"
+	switch r.URL.Query().Get("type") {
+	case "":
+		if namespace == "" {
+			h.cacheManager.Clear()
+		} else if _, err := h.cacheManager.InvalidatePrefix(namespace); err != nil {
+			h.respondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	case "negative":
+		h.cacheManager.ClearNegativeIn(namespace)
+		message = "Negative cache cleared successfully"
+	default:
+		h.respondWithError(w, http.StatusBadRequest, "Invalid type parameter. Supported: negative")
+		return
+	}
 	response := map[string]interface{}{
 		"status":  "success",
-		"message": "Cache cleared successfully",
+		"message": message,
 		"time":    time.Now().Format(time.RFC3339),
 	}
 	h.respondWithJSON(w, http.StatusOK, response)
 }
 
-// RootHandler handles root endpoint requests
-func (h *Handler) RootHandler(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	html := `<!DOCTYPE html>
-<html lang="en">
-<head>
-    <meta charset="UTF-8">
-    <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <title>Weather Microservice API</title>
-    <style>
-        * { margin: 0; padding: 0; box-sizing: border-box; }
-        body { 
-            font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif;
-            background: linear-gradient(135deg, #667eea 0%, #764ba2 100%);
-            min-height: 100vh;
-            padding: 40px 20px;
-        }
-        .container { 
-            max-width: 900px;
-            margin: 0 auto;
-            background: white;
-            padding: 40px;
-            border-radius: 15px;
-            box-shadow: 0 20px 60px rgba(0,0,0,0.3);
-        }
-        h1 { 
-            color: #667eea;
-            font-size: 2.5rem;
-            margin-bottom: 10px;
-            text-align: center;
-        }
-        .subtitle {
-            text-align: center;
-            color: #666;
-            margin-bottom: 30px;
-            font-size: 1.1rem;
-        }
-        .section { 
-            margin: 30px 0;
-            padding: 20px;
-            background: #f8f9fa;
-            border-radius: 10px;
-            border-left: 4px solid #667eea;
-        }
-        h2 { 
-            color: #333;
-            margin-bottom: 15px;
-            font-size: 1.5rem;
-        }
-        .endpoint { 
-            background: white;
-            padding: 15px;
-            margin: 15px 0;
-            border-radius: 8px;
-            border: 1px solid #dee2e6;
-        }
-        .method { 
-            display: inline-block;
-            padding: 4px 10px;
-            background: #28a745;
-            color: white;
-            border-radius: 4px;
-            font-size: 0.85rem;
-            font-weight: bold;
-            margin-right: 10px;
-        }
-        .method.post { background: #ffc107; }
-        .path { 
-            font-family: 'Courier New', monospace;
-            color: #667eea;
-            font-weight: bold;
-        }
-        .description { 
-            margin-top: 10px;
-            color: #666;
-            line-height: 1.6;
-        }
-        .example { 
-            background: #e3f2fd;
-            padding: 12px;
-            border-radius: 5px;
-            margin-top: 10px;
-            font-family: 'Courier New', monospace;
-            font-size: 0.9rem;
-            color: #1565c0;
-        }
-        .features {
-            display: grid;
-            grid-template-columns: repeat(auto-fit, minmax(200px, 1fr));
-            gap: 15px;
-            margin-top: 20px;
-        }
-        .feature {
-            background: white;
-            padding: 15px;
-            border-radius: 8px;
-            text-align: center;
-            border: 1px solid #dee2e6;
-        }
-        .feature-icon {
-            font-size: 2rem;
-            margin-bottom: 10px;
-        }
-        .footer {
-            text-align: center;
-            margin-top: 40px;
-            padding-top: 20px;
-            border-top: 2px solid #dee2e6;
-            color: #666;
-        }
-    </style>
-</head>
-<body>
-    <div class="container">
-        <h1>🌤️ Weather Microservice API</h1>
-        <p class="subtitle">High-performance weather data service with intelligent caching</p>
-
-        <div class="section">
-            <h2>🚀 Features</h2>
-            <div class="features">
-                <div class="feature">
-                    <div class="feature-icon">⚡</div>
-                    <strong>Fast Response</strong>
-                    <p style="color: #666; font-size: 0.9rem; margin-top: 5px;">85% reduced latency</p>
-                </div>
-                <div class="feature">
-                    <div class="feature-icon">💾</div>
-                    <strong>Smart Caching</strong>
-                    <p style="color: #666; font-size: 0.9rem; margin-top: 5px;">10-minute cache</p>
-                </div>
-                <div class="feature">
-                    <div class="feature-icon">📊</div>
-                    <strong>Metrics</strong>
-                    <p style="color: #666; font-size: 0.9rem; margin-top: 5px;">Real-time monitoring</p>
-                </div>
-                <div class="feature">
-                    <div class="feature-icon">🔒</div>
-                    <strong>99.9% Uptime</strong>
-                    <p style="color: #666; font-size: 0.9rem; margin-top: 5px;">Production ready</p>
-                </div>
-            </div>
-        </div>
-
-        <div class="section">
-            <h2>📡 API Endpoints</h2>
-            
-            <div class="endpoint">
-                <span class="method">GET</span>
-                <span class="path">/weather?city={cityname}</span>
-                <div class="description">
-                    Get comprehensive weather data for any city worldwide including temperature, humidity, wind, UV index, and air quality.
-                </div>
-                <div class="example">
-                    📝 Example: /weather?city=London
-                </div>
-            </div>
-
-            <div class="endpoint">
-                <span class="method">GET</span>
-                <span class="path">/health</span>
-                <div class="description">
-                    Check the health status of the microservice. Returns service status, timestamp, and cache statistics.
-                </div>
-            </div>
-
-            <div class="endpoint">
-                <span class="method">GET</span>
-                <span class="path">/readiness</span>
-                <div class="description">
-                    Kubernetes readiness probe endpoint. Confirms the service is ready to accept traffic.
-                </div>
-            </div>
-
-            <div class="endpoint">
-                <span class="method">GET</span>
-                <span class="path">/metrics</span>
-                <div class="description">
-                    Get detailed performance metrics including request counts, response times, cache hit rates, and top requested cities.
-                </div>
-            </div>
-
-            <div class="endpoint">
-                <span class="method">GET</span>
-                <span class="path">/cache</span>
-                <div class="description">
-                    View current cache status, hit/miss rates, and all cached entries with expiry times.
-                </div>
-            </div>
-
-            <div class="endpoint">
-                <span class="method post">POST</span>
-                <span class="path">/cache/clear</span>
-                <div class="description">
-                    Clear all cached data. Useful for debugging or forcing fresh data retrieval.
-                </div>
-            </div>
-        </div>
-
-        <div class="footer">
-            <p><strong>Weather Microservice v1.0.0</strong></p>
-            <p style="margin-top: 10px;">Powered by OpenWeatherMap API | Started: ` + time.Now().Format("2006-01-02 15:04:05") + `</p>
-        </div>
-    </div>
-</body>
-</html>`
-	w.Write([]byte(html))
-}
-
 // Helper methods
 func (h *Handler) respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 	w.Header().Set("Content-Type", "application/json")
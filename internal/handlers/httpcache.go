@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/models"
+)
+
+// weatherETag derives a weak ETag from the cached weather content. The
+// cache_hit flag is excluded so the tag is stable across hits and misses.
+// variant names the representation, such as the single unit system, so that
+// each representation of the same data gets its own tag.
+func weatherETag(data models.WeatherData, variant string) string {
+	data.CacheHit = false
+	bytes, err := json.Marshal(data)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(append(bytes, variant...))
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// setCacheHeaders sets ETag, Last-Modified and Cache-Control for weather data
+// and reports whether the client's conditional headers allow a 304 response
+func setCacheHeaders(w http.ResponseWriter, r *http.Request, data *models.WeatherData, variant string) bool {
+	etag := weatherETag(*data, variant)
+	if etag != "" {
+		w.Header().Set("ETag", etag)
+	}
+
+	var lastModified time.Time
+	if data.Dt > 0 {
+		lastModified = time.Unix(data.Dt, 0).UTC()
+		w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+	}
+
+	maxAge := 0
+	if !data.ExpiresAt.IsZero() {
+		if remaining := time.Until(data.ExpiresAt); remaining > 0 {
+			maxAge = int(remaining.Seconds())
+		}
+	}
+	// Responses for authenticated callers must not be stored by shared caches
+	visibility := "public"
+	if r.Header.Get("Authorization") != "" || r.Header.Get("X-API-Key") != "" {
+		visibility = "private"
+	}
+	w.Header().Set("Cache-Control", fmt.Sprintf("%s, max-age=%d", visibility, maxAge))
+
+	return notModified(r, etag, lastModified)
+}
+
+// notModified evaluates If-None-Match, falling back to If-Modified-Since
+// only when no entity tag was sent (RFC 9110 section 13.2.2)
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		if etag == "" {
+			return false
+		}
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || weakMatch(candidate, etag) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(ims)
+		if err != nil {
+			return false
+		}
+		return !lastModified.After(since)
+	}
+
+	return false
+}
+
+// weakMatch compares entity tags using the weak comparison function
+func weakMatch(a, b string) bool {
+	return strings.TrimPrefix(a, "W/") == strings.TrimPrefix(b, "W/")
+}
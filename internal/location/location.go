@@ -0,0 +1,133 @@
+package location
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// StrategyGrid snaps coordinates to a fixed grid measured in degrees
+	StrategyGrid = "grid"
+	// StrategyGeohash snaps coordinates to the centre of a geohash cell
+	StrategyGeohash = "geohash"
+
+	// DefaultGridDegrees is roughly 1.1 km of latitude
+	DefaultGridDegrees = 0.01
+	// DefaultGeohashPrecision gives cells of roughly 1.2 km x 0.6 km
+	DefaultGeohashPrecision = 6
+)
+
+// CanonicalCity normalizes a city name for cache keys and upstream lookups:
+// Unicode NFKC normalization, lower case, and surrounding and repeated
+// whitespace collapsed to single spaces
+func CanonicalCity(city string) string {
+	city = norm.NFKC.String(city)
+	return strings.Join(strings.Fields(strings.ToLower(city)), " ")
+}
+
+// ValidCoordinates reports whether lat and lon are on the globe
+func ValidCoordinates(lat, lon float64) bool {
+	return lat >= -90 && lat <= 90 && lon >= -180 && lon <= 180
+}
+
+// Keyer maps nearby coordinates to one shared cache key
+type Keyer interface {
+	// Snap returns the representative point of the cell containing lat/lon
+	Snap(lat, lon float64) (float64, float64)
+	// Key returns the cache key for the cell containing lat/lon
+	Key(lat, lon float64) string
+}
+
+// NewKeyer returns the keyer for strategy. An empty strategy uses the grid.
+func NewKeyer(strategy string, gridDegrees float64, geohashPrecision int) (Keyer, error) {
+	switch strategy {
+	case "", StrategyGrid:
+		if gridDegrees <= 0 || gridDegrees > 10 {
+			return nil, fmt.Errorf("grid size must be between 0 and 10 degrees, got %v", gridDegrees)
+		}
+		return gridKeyer{size: gridDegrees}, nil
+	case StrategyGeohash:
+		if geohashPrecision < 1 || geohashPrecision > 12 {
+			return nil, fmt.Errorf("geohash precision must be between 1 and 12, got %d", geohashPrecision)
+		}
+		return geohashKeyer{precision: geohashPrecision}, nil
+	default:
+		return nil, fmt.Errorf("unknown location key strategy '%s' (expected 'grid' or 'geohash')", strategy)
+	}
+}
+
+// DefaultKeyer snaps coordinates to the default grid
+func DefaultKeyer() Keyer {
+	return gridKeyer{size: DefaultGridDegrees}
+}
+
+// gridKeyer snaps coordinates to the nearest multiple of size degrees
+type gridKeyer struct {
+	size float64
+}
+
+func (g gridKeyer) Snap(lat, lon float64) (float64, float64) {
+	return g.snap(lat, 90), g.snap(lon, 180)
+}
+
+// snap rounds v to the grid and clamps it to [-limit, limit], since a grid
+// size that does not divide the limit can round past the poles or the
+// antimeridian
+func (g gridKeyer) snap(v, limit float64) float64 {
+	snapped := math.Round(v/g.size) * g.size
+	snapped = math.Max(-limit, math.Min(limit, snapped))
+	// Trim floating point noise such as 51.510000000000005
+	return math.Round(snapped*1e6) / 1e6
+}
+
+func (g gridKeyer) Key(lat, lon float64) string {
+	lat, lon = g.Snap(lat, lon)
+	return fmt.Sprintf("grid:%g,%g", lat, lon)
+}
+
+// geohashKeyer keys coordinates by their geohash at a fixed precision
+type geohashKeyer struct {
+	precision int
+}
+
+func (g geohashKeyer) Snap(lat, lon float64) (float64, float64) {
+	return decodeGeohashCenter(encodeGeohash(lat, lon, g.precision))
+}
+
+func (g geohashKeyer) Key(lat, lon float64) string {
+	return "geohash:" + encodeGeohash(lat, lon, g.precision)
+}
+
+// ParseKey returns the point a grid or geohash cache key stands for
+func ParseKey(key string) (float64, float64, bool) {
+	strategy, value, found := strings.Cut(key, ":")
+	if !found {
+		return 0, 0, false
+	}
+
+	switch strategy {
+	case StrategyGrid:
+		latText, lonText, found := strings.Cut(value, ",")
+		if !found {
+			return 0, 0, false
+		}
+		lat, latErr := strconv.ParseFloat(latText, 64)
+		lon, lonErr := strconv.ParseFloat(lonText, 64)
+		if latErr != nil || lonErr != nil || !ValidCoordinates(lat, lon) {
+			return 0, 0, false
+		}
+		return lat, lon, true
+	case StrategyGeohash:
+		if value == "" || len(value) > 12 || strings.Trim(value, geohashAlphabet) != "" {
+			return 0, 0, false
+		}
+		lat, lon := decodeGeohashCenter(value)
+		return lat, lon, true
+	default:
+		return 0, 0, false
+	}
+}
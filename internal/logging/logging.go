@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// Level is a log verbosity threshold
+type Level int32
+
+const (
+	Debug Level = iota
+	Info
+	Warn
+	Error
+)
+
+// Levels lists the accepted level names in increasing severity
+var Levels = []string{"debug", "info", "warn", "error"}
+
+var current atomic.Int32
+
+func init() {
+	current.Store(int32(Info))
+}
+
+// ParseLevel parses a level name such as "info"
+func ParseLevel(name string) (Level, error) {
+	for i, level := range Levels {
+		if strings.EqualFold(name, level) {
+			return Level(i), nil
+		}
+	}
+	return Info, fmt.Errorf("unknown log level '%s' (expected one of %s)", name, strings.Join(Levels, ", "))
+}
+
+// String returns the level name
+func (l Level) String() string {
+	if l < Debug || l > Error {
+		return fmt.Sprintf("level(%d)", int32(l))
+	}
+	return Levels[l]
+}
+
+// SetLevel changes the process-wide level; safe to call while serving
+func SetLevel(l Level) {
+	current.Store(int32(l))
+}
+
+// CurrentLevel returns the process-wide level
+func CurrentLevel() Level {
+	return Level(current.Load())
+}
+
+// Enabled reports whether messages at level l should be logged
+func Enabled(l Level) bool {
+	return l >= CurrentLevel()
+}
@@ -11,18 +11,31 @@ type MetricsManager struct {
 	successRequests   int64
 	cacheHits         int64
 	cacheMisses       int64
+	negativeHits      int64
 	errors            int64
 	responseTimes     []float64
 	cityRequestCounts map[string]int64
+	tenantUsage       map[string]*TenantUsage
 	startTime         time.Time
 	mu                sync.RWMutex
 }
 
+// TenantUsage holds per-tenant counters used for usage chargeback
+type TenantUsage struct {
+	Requests      int64 `json:"requests"`
+	CacheHits     int64 `json:"cache_hits"`
+	CacheMisses   int64 `json:"cache_misses"`
+	NegativeHits  int64 `json:"negative_hits"`
+	Errors        int64 `json:"errors"`
+	UpstreamCalls int64 `json:"upstream_calls"`
+}
+
 // NewMetricsManager creates a new metrics manager
 func NewMetricsManager() *MetricsManager {
 	return &MetricsManager{
 		responseTimes:     make([]float64, 0, 1000),
 		cityRequestCounts: make(map[string]int64),
+		tenantUsage:       make(map[string]*TenantUsage),
 		startTime:         time.Now(),
 	}
 }
@@ -46,11 +59,26 @@ func (m *MetricsManager) RecordRequest(duration time.Duration, cacheHit bool, er
 		m.successRequests++
 	}
 
-	// Record response time
+	m.recordResponseTime(duration)
+}
+
+// RecordNegativeHit records a request answered from the negative cache. It
+// is neither a cache hit nor an error: the city is known not to exist.
+func (m *MetricsManager) RecordNegativeHit(duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.totalRequests++
+	m.negativeHits++
+	m.recordResponseTime(duration)
+}
+
+// recordResponseTime keeps the last 1000 response times; callers must hold
+// the lock
+func (m *MetricsManager) recordResponseTime(duration time.Duration) {
 	durationMs := float64(duration.Milliseconds())
 	m.responseTimes = append(m.responseTimes, durationMs)
 
-	// Keep only last 1000 response times
 	if len(m.responseTimes) > 1000 {
 		m.responseTimes = m.responseTimes[1:]
 	}
@@ -63,6 +91,51 @@ func (m *MetricsManager) RecordCityRequest(city string) {
 	m.cityRequestCounts[city]++
 }
 
+// RecordTenantRequest records a request made on behalf of a tenant
+func (m *MetricsManager) RecordTenantRequest(tenantID string, cacheHit bool, upstreamCalls int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	usage, exists := m.tenantUsage[tenantID]
+	if !exists {
+		usage = &TenantUsage{}
+		m.tenantUsage[tenantID] = usage
+	}
+
+	usage.Requests++
+	if cacheHit {
+		usage.CacheHits++
+	} else {
+		usage.CacheMisses++
+	}
+	if err != nil {
+		usage.Errors++
+	}
+	usage.UpstreamCalls += int64(upstreamCalls)
+}
+
+// RecordTenantNegativeHit records a request a tenant made for a city that is
+// negatively cached
+func (m *MetricsManager) RecordTenantNegativeHit(tenantID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	usage, exists := m.tenantUsage[tenantID]
+	if !exists {
+		usage = &TenantUsage{}
+		m.tenantUsage[tenantID] = usage
+	}
+	usage.Requests++
+	usage.NegativeHits++
+}
+
+// GetTenantUsage returns a copy of the usage counters for every tenant
+func (m *MetricsManager) GetTenantUsage() map[string]TenantUsage {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.copyTenantUsage()
+}
+
 // GetMetrics returns all metrics
 func (m *MetricsManager) GetMetrics() map[string]interface{} {
 	m.mu.RLock()
@@ -82,6 +155,7 @@ func (m *MetricsManager) GetMetrics() map[string]interface{} {
 		"cache_hits":            m.cacheHits,
 		"cache_misses":          m.cacheMisses,
 		"cache_hit_rate":        m.calculateCacheHitRate(),
+		"negative_hits":         m.negativeHits,
 		"errors":                m.errors,
 		"error_rate":            m.calculateErrorRate(),
 		"average_response_ms":   avgResponseTime,
@@ -92,7 +166,17 @@ func (m *MetricsManager) GetMetrics() map[string]interface{} {
 		"requests_per_minute":   m.calculateRequestsPerMinute(uptime),
 		"top_cities":            topCities,
 		"total_unique_cities":   len(m.cityRequestCounts),
+		"tenants":               m.copyTenantUsage(),
+	}
+}
+
+// copyTenantUsage copies tenant counters; callers must hold the lock
+func (m *MetricsManager) copyTenantUsage() map[string]TenantUsage {
+	usage := make(map[string]TenantUsage, len(m.tenantUsage))
+	for id, u := range m.tenantUsage {
+		usage[id] = *u
 	}
+	return usage
 }
 
 // calculateAverageResponseTime calculates average response time
@@ -208,8 +292,10 @@ func (m *MetricsManager) Reset() {
 	m.successRequests = 0
 	m.cacheHits = 0
 	m.cacheMisses = 0
+	m.negativeHits = 0
 	m.errors = 0
 	m.responseTimes = make([]float64, 0, 1000)
 	m.cityRequestCounts = make(map[string]int64)
+	m.tenantUsage = make(map[string]*TenantUsage)
 	m.startTime = time.Now()
 }
\ No newline at end of file
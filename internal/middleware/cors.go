@@ -0,0 +1,196 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/mux"
+)
+
+// CORSOptions configures the CORS policy
+type CORSOptions struct {
+	// AllowedOrigins lists exact origins ("https://app.example.com"), wildcard
+	// subdomains ("https://*.example.com") or "*" for any origin
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	AllowCredentials bool
+	MaxAgeSeconds    int
+}
+
+// CORSPolicy applies a CORS policy to requests
+type CORSPolicy struct {
+	mu      sync.RWMutex
+	options CORSOptions
+	methods map[string]bool
+	headers map[string]bool
+}
+
+// NewCORSPolicy creates a CORS policy from the given options
+func NewCORSPolicy(opts CORSOptions) *CORSPolicy {
+	p := &CORSPolicy{}
+	p.Update(opts)
+	return p
+}
+
+// Update atomically replaces the policy options
+func (p *CORSPolicy) Update(opts CORSOptions) {
+	methods := make(map[string]bool, len(opts.AllowedMethods))
+	normalized := make([]string, len(opts.AllowedMethods))
+	for i, m := range opts.AllowedMethods {
+		normalized[i] = strings.ToUpper(m)
+		methods[normalized[i]] = true
+	}
+	opts.AllowedMethods = normalized
+	headers := make(map[string]bool, len(opts.AllowedHeaders))
+	for _, h := range opts.AllowedHeaders {
+		headers[strings.ToLower(h)] = true
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.options = opts
+	p.methods = methods
+	p.headers = headers
+}
+
+// Middleware adds CORS headers for allowed origins and answers preflight requests.
+// It must wrap the router rather than be registered with router.Use, because
+// preflight requests do not match any route's method.
+func (p *CORSPolicy) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		p.mu.RLock()
+		opts, methods, headers := p.options, p.methods, p.headers
+		p.mu.RUnlock()
+
+		w.Header().Add("Vary", "Origin")
+		if !originAllowed(opts.AllowedOrigins, origin) {
+			if preflight {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !preflight {
+			setAllowOrigin(w, opts, origin)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Access-Control-Request-Method")
+		w.Header().Add("Vary", "Access-Control-Request-Headers")
+
+		if !methods[strings.ToUpper(r.Header.Get("Access-Control-Request-Method"))] {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		for _, h := range strings.Split(r.Header.Get("Access-Control-Request-Headers"), ",") {
+			h = strings.ToLower(strings.TrimSpace(h))
+			if h != "" && !headers[h] {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+		}
+
+		setAllowOrigin(w, opts, origin)
+		w.Header().Set("Access-Control-Allow-Methods", strings.Join(opts.AllowedMethods, ", "))
+		if len(opts.AllowedHeaders) > 0 {
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(opts.AllowedHeaders, ", "))
+		}
+		if opts.MaxAgeSeconds > 0 {
+			w.Header().Set("Access-Control-Max-Age", strconv.Itoa(opts.MaxAgeSeconds))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// setAllowOrigin writes the origin headers for an allowed request. An origin
+// listed explicitly is echoed back; one admitted only by "*" gets "*", which
+// browsers never honour for credentialed requests.
+func setAllowOrigin(w http.ResponseWriter, opts CORSOptions, origin string) {
+	if !originListed(opts.AllowedOrigins, origin) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		return
+	}
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	if opts.AllowCredentials {
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// originAllowed matches an origin against "*", exact and wildcard subdomain
+// patterns
+func originAllowed(allowed []string, origin string) bool {
+	for _, pattern := range allowed {
+		if pattern == "*" {
+			return true
+		}
+	}
+	return originListed(allowed, origin)
+}
+
+// originListed matches an origin against exact and wildcard subdomain
+// patterns, ignoring "*"
+func originListed(allowed []string, origin string) bool {
+	for _, pattern := range allowed {
+		if strings.EqualFold(pattern, origin) || matchWildcardOrigin(pattern, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchWildcardOrigin matches patterns like "https://*.example.com"; the
+// wildcard only matches subdomains, never the bare domain
+func matchWildcardOrigin(pattern, origin string) bool {
+	scheme, host, found := strings.Cut(pattern, "://*.")
+	if !found {
+		return false
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil || !strings.EqualFold(u.Scheme, scheme) {
+		return false
+	}
+
+	suffix := "." + strings.ToLower(host)
+	originHost := strings.ToLower(u.Host)
+	return strings.HasSuffix(originHost, suffix) && len(originHost) > len(suffix)
+}
+
+// RouteMethods returns the sorted set of methods served by the router's
+// routes, plus OPTIONS for preflight requests
+func RouteMethods(router *mux.Router) []string {
+	set := map[string]bool{http.MethodOptions: true}
+	router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+		for _, m := range methods {
+			set[m] = true
+		}
+		return nil
+	})
+
+	methods := make([]string, 0, len(set))
+	for m := range set {
+		methods = append(methods, m)
+	}
+	sort.Strings(methods)
+	return methods
+}
@@ -1,13 +1,22 @@
 package middleware
 
 import (
+	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/auth"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/logging"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/models"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/tenant"
 )
 
-// LoggingMiddleware logs all HTTP requests
+// LoggingMiddleware logs all HTTP requests at the info level
 func LoggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
@@ -17,6 +26,9 @@ func LoggingMiddleware(next http.Handler) http.Handler {
 		
 		next.ServeHTTP(wrappedWriter, r)
 		
+		if !logging.Enabled(logging.Info) {
+			return
+		}
 		duration := time.Since(start)
 		log.Printf("[%s] %s %s - Status: %d - Duration: %v - IP: %s",
 			r.Method,
@@ -29,24 +41,6 @@ func LoggingMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-// CORSMiddleware adds CORS headers
-func CORSMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-		w.Header().Set("Access-Control-Max-Age", "3600")
-
-		// Handle preflight requests
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
-		}
-
-		next.ServeHTTP(w, r)
-	})
-}
-
 // RecoveryMiddleware recovers from panics
 func RecoveryMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -62,6 +56,29 @@ func RecoveryMiddleware(next http.Handler) http.Handler {
 
 // RateLimitMiddleware implements simple rate limiting
 func RateLimitMiddleware(requestsPerMinute int) func(http.Handler) http.Handler {
+	return NewRateLimiter(requestsPerMinute).Middleware
+}
+
+// RateLimiter limits requests per client IP to a limit that can be changed
+// while serving
+type RateLimiter struct {
+	limit atomic.Int64
+}
+
+// NewRateLimiter creates a limiter allowing requestsPerMinute per client IP
+func NewRateLimiter(requestsPerMinute int) *RateLimiter {
+	rl := &RateLimiter{}
+	rl.SetLimit(requestsPerMinute)
+	return rl
+}
+
+// SetLimit changes the per-minute limit; windows already open keep their count
+func (rl *RateLimiter) SetLimit(requestsPerMinute int) {
+	rl.limit.Store(int64(requestsPerMinute))
+}
+
+// Middleware applies the limiter to next
+func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
 	type client struct {
 		requests  int
 		resetTime time.Time
@@ -88,39 +105,145 @@ func RateLimitMiddleware(requestsPerMinute int) func(http.Handler) http.Handler
 		}
 	}()
 
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := r.RemoteAddr
+
+		mu.Lock()
+		c, exists := clients[ip]
+		now := time.Now()
+
+		if !exists || now.After(c.resetTime) {
+			clients[ip] = &client{
+				requests:  1,
+				resetTime: now.Add(1 * time.Minute),
+			}
+			mu.Unlock()
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if int64(c.requests) >= rl.limit.Load() {
+			mu.Unlock()
+			log.Printf("⚠️  Rate limit exceeded for IP: %s", ip)
+			writeError(w, http.StatusTooManyRequests, "Rate limit exceeded. Please try again later.")
+			return
+		}
+
+		c.requests++
+		mu.Unlock()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// AuthMiddleware validates JWT bearer tokens and requires the given scope.
+// A nil verifier disables authentication.
+func AuthMiddleware(verifier *auth.Verifier, scope string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
+		if verifier == nil {
+			return next
+		}
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			ip := r.RemoteAddr
+			header := r.Header.Get("Authorization")
+			if len(header) < 7 || !strings.EqualFold(header[:7], "Bearer ") {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="weather"`)
+				writeError(w, http.StatusUnauthorized, "Missing bearer token")
+				return
+			}
 
-			mu.Lock()
-			c, exists := clients[ip]
-			now := time.Now()
+			claims, err := verifier.Verify(strings.TrimSpace(header[7:]))
+			if err != nil {
+				log.Printf("⚠️  Rejected token from %s: %v", r.RemoteAddr, err)
+				w.Header().Set("WWW-Authenticate", `Bearer realm="weather", error="invalid_token"`)
+				writeError(w, http.StatusUnauthorized, "Invalid or expired token")
+				return
+			}
 
-			if !exists || now.After(c.resetTime) {
-				clients[ip] = &client{
-					requests:  1,
-					resetTime: now.Add(1 * time.Minute),
-				}
-				mu.Unlock()
-				next.ServeHTTP(w, r)
+			if !claims.HasScope(scope) {
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="weather", error="insufficient_scope", scope="%s"`, scope))
+				writeError(w, http.StatusForbidden, fmt.Sprintf("Token is missing required scope '%s'", scope))
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithClaims(r.Context(), claims)))
+		})
+	}
+}
+
+// TenantMiddleware resolves the calling tenant from its credentials, enforces
+// the tenant's endpoint allow list and rate limit, and stores it in the request context
+func TenantMiddleware(registry *tenant.Registry) func(http.Handler) http.Handler {
+	type window struct {
+		requests  int
+		resetTime time.Time
+	}
+
+	var (
+		windows = make(map[string]*window)
+		mu      sync.Mutex
+	)
+
+	allow := func(t *tenant.Tenant) bool {
+		if t.RateLimitPerMinute <= 0 {
+			return true
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		now := time.Now()
+		w, exists := windows[t.ID]
+		if !exists || now.After(w.resetTime) {
+			windows[t.ID] = &window{requests: 1, resetTime: now.Add(1 * time.Minute)}
+			return true
+		}
+		if w.requests >= t.RateLimitPerMinute {
+			return false
+		}
+		w.requests++
+		return true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tokenTenant := ""
+			if claims, ok := auth.ClaimsFromContext(r.Context()); ok {
+				tokenTenant = claims.Tenant
+			}
+
+			t, found := registry.Resolve(r, tokenTenant)
+			if !found {
+				writeError(w, http.StatusUnauthorized, "Unknown tenant. Provide a valid X-API-Key header")
 				return
 			}
 
-			if c.requests >= requestsPerMinute {
-				mu.Unlock()
-				log.Printf("⚠️  Rate limit exceeded for IP: %s", ip)
-				http.Error(w, "Rate limit exceeded. Please try again later.", http.StatusTooManyRequests)
+			if !t.Allows(r.URL.Path) {
+				writeError(w, http.StatusForbidden, fmt.Sprintf("Tenant '%s' is not allowed to access %s", t.ID, r.URL.Path))
 				return
 			}
 
-			c.requests++
-			mu.Unlock()
+			if !allow(t) {
+				log.Printf("⚠️  Rate limit exceeded for tenant: %s", t.ID)
+				writeError(w, http.StatusTooManyRequests, "Tenant rate limit exceeded. Please try again later.")
+				return
+			}
 
-			next.ServeHTTP(w, r)
+			next.ServeHTTP(w, r.WithContext(tenant.WithTenant(r.Context(), t)))
 		})
 	}
 }
 
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(models.ErrorResponse{
+		Error:   message,
+		Message: message,
+		Code:    code,
+	})
+}
+
 // responseWriter wraps http.ResponseWriter to capture status code
 type responseWriter struct {
 	http.ResponseWriter
@@ -130,4 +253,14 @@ type responseWriter struct {
 func (rw *responseWriter) WriteHeader(code int) {
 	rw.statusCode = code
 	rw.ResponseWriter.WriteHeader(code)
+}
+
+// Flush forwards to the underlying writer so streaming responses work
+func (rw *responseWriter) Flush() {
+	http.NewResponseController(rw.ResponseWriter).Flush()
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
 }
\ No newline at end of file
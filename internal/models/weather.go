@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // OpenWeatherResponse represents the response from OpenWeatherMap API
 type OpenWeatherResponse struct {
 	Name  string `json:"name"`
@@ -84,6 +86,7 @@ type WeatherData struct {
 	Wind struct {
 		Speed     float64 `json:"speed_ms"`
 		SpeedKmh  float64 `json:"speed_kmh"`
+		SpeedMph  float64 `json:"speed_mph"`
 		Direction string  `json:"direction"`
 		Degrees   int     `json:"degrees"`
 	} `json:"wind"`
@@ -96,6 +99,7 @@ type WeatherData struct {
 		Icon        string `json:"icon"`
 	} `json:"weather"`
 	Visibility  int     `json:"visibility_meters"`
+	Dt          int64   `json:"dt"`
 	Sunrise     int64   `json:"sunrise"`
 	Sunset      int64   `json:"sunset"`
 	SunriseTime string  `json:"sunrise_time"`
@@ -107,8 +111,30 @@ type WeatherData struct {
 		Latitude  float64 `json:"latitude"`
 		Longitude float64 `json:"longitude"`
 	} `json:"coordinates"`
-	LastUpdated string `json:"last_updated"`
-	CacheHit    bool   `json:"cache_hit"`
+	LastUpdated string    `json:"last_updated"`
+	CacheHit    bool      `json:"cache_hit"`
+	ExpiresAt   time.Time `json:"-"`
+}
+
+// SingleUnitWeatherData is WeatherData with temperatures and wind speed in a
+// single unit system instead of all of them. Its main and wind objects
+// replace the ones of the embedded WeatherData.
+type SingleUnitWeatherData struct {
+	WeatherData
+	Units string `json:"units"`
+	Main  struct {
+		Temp      float64 `json:"temp"`
+		FeelsLike float64 `json:"feels_like"`
+		TempMin   float64 `json:"temp_min"`
+		TempMax   float64 `json:"temp_max"`
+		Humidity  int     `json:"humidity"`
+		Pressure  int     `json:"pressure"`
+	} `json:"main"`
+	Wind struct {
+		Speed     float64 `json:"speed"`
+		Direction string  `json:"direction"`
+		Degrees   int     `json:"degrees"`
+	} `json:"wind"`
 }
 
 // ErrorResponse represents an error response
@@ -0,0 +1,328 @@
+// Interactive documentation for the OpenAPI document at /openapi.json. Every
+// operation can be tried out against this server. Text from the document is
+// only ever inserted with textContent, never as HTML.
+(function () {
+    "use strict";
+
+    var methods = ["get", "post", "put", "patch", "delete"];
+    var credentials = { bearer: "", apiKey: "" };
+
+    // el creates an element with the given class and text
+    function el(tag, className, text) {
+        var node = document.createElement(tag);
+        if (className) {
+            node.className = className;
+        }
+        if (text !== undefined && text !== null) {
+            node.textContent = text;
+        }
+        return node;
+    }
+
+    function renderInfo(doc) {
+        var info = document.getElementById("info");
+        info.textContent = "";
+        var title = el("h1", "", doc.info.title + " ");
+        title.appendChild(el("small", "", doc.info.version));
+        info.appendChild(title);
+        if (doc.info.description) {
+            info.appendChild(el("p", "", doc.info.description));
+        }
+        var raw = el("p", "", "OpenAPI document: ");
+        var link = el("a", "", "/openapi.json");
+        link.href = "/openapi.json";
+        raw.appendChild(link);
+        info.appendChild(raw);
+    }
+
+    // renderAuth lets callers set the credentials sent with every request
+    function renderAuth(doc, container) {
+        var schemes = (doc.components && doc.components.securitySchemes) || {};
+        var section = el("section", "auth");
+        section.appendChild(el("h3", "", "Authorization"));
+        Object.keys(schemes).sort().forEach(function (name) {
+            var scheme = schemes[name];
+            var field = scheme.type === "http" ? "bearer" : "apiKey";
+            var label = el("label", "", (scheme.type === "http" ? "Bearer token" : scheme.name) + " ");
+            var input = el("input");
+            input.type = "password";
+            input.autocomplete = "off";
+            input.placeholder = scheme.description || "";
+            input.addEventListener("input", function () {
+                credentials[field] = input.value.trim();
+            });
+            label.appendChild(input);
+            section.appendChild(label);
+        });
+        container.appendChild(section);
+    }
+
+    // operations lists every operation sorted by path and method
+    function operations(doc) {
+        var list = [];
+        Object.keys(doc.paths).sort().forEach(function (path) {
+            methods.forEach(function (method) {
+                var operation = doc.paths[path][method];
+                if (operation) {
+                    list.push({ path: path, method: method, operation: operation });
+                }
+            });
+        });
+        return list;
+    }
+
+    function renderParameters(parameters, inputs) {
+        var table = el("table");
+        var head = el("tr");
+        ["Parameter", "In", "Description", "Value"].forEach(function (name) {
+            head.appendChild(el("th", "", name));
+        });
+        table.appendChild(head);
+
+        parameters.forEach(function (parameter) {
+            var row = el("tr");
+            var name = el("td");
+            name.appendChild(el("code", "", parameter.name));
+            if (parameter.required) {
+                name.appendChild(el("span", "required", " required"));
+            }
+            row.appendChild(name);
+            row.appendChild(el("td", "", parameter.in));
+            row.appendChild(el("td", "", parameter.description || ""));
+
+            var schema = parameter.schema || {};
+            var input;
+            if (schema.enum) {
+                input = el("select");
+                input.appendChild(el("option", "", ""));
+                schema.enum.forEach(function (value) {
+                    input.appendChild(el("option", "", value));
+                });
+            } else {
+                input = el("input");
+                input.type = "text";
+                if (schema.example !== undefined) {
+                    input.placeholder = String(schema.example);
+                }
+            }
+            inputs.push({ parameter: parameter, input: input });
+            var value = el("td");
+            value.appendChild(input);
+            row.appendChild(value);
+            table.appendChild(row);
+        });
+        return table;
+    }
+
+    function renderResponses(responses) {
+        var table = el("table");
+        var head = el("tr");
+        head.appendChild(el("th", "", "Code"));
+        head.appendChild(el("th", "", "Description"));
+        table.appendChild(head);
+        Object.keys(responses).sort().forEach(function (code) {
+            var row = el("tr");
+            row.appendChild(el("td", "", code));
+            row.appendChild(el("td", "", responses[code].description));
+            table.appendChild(row);
+        });
+        return table;
+    }
+
+    // execute sends the operation with the entered parameters and shows the
+    // response
+    function execute(entry, inputs, button, result) {
+        var path = entry.path;
+        var query = new URLSearchParams();
+        var headers = {};
+        var missing = false;
+
+        inputs.forEach(function (item) {
+            var value = item.input.value.trim();
+            var required = item.parameter.required === true;
+            item.input.classList.toggle("missing", required && value === "");
+            if (value === "") {
+                missing = missing || required;
+                return;
+            }
+            switch (item.parameter.in) {
+            case "path":
+                path = path.replace("{" + item.parameter.name + "}", encodeURIComponent(value));
+                break;
+            case "query":
+                query.append(item.parameter.name, value);
+                break;
+            case "header":
+                headers[item.parameter.name] = value;
+                break;
+            }
+        });
+        if (missing) {
+            return;
+        }
+        if (credentials.bearer) {
+            headers.Authorization = "Bearer " + credentials.bearer;
+        }
+        if (credentials.apiKey) {
+            headers["X-API-Key"] = credentials.apiKey;
+        }
+
+        var url = path + (query.toString() ? "?" + query.toString() : "");
+        result.textContent = "";
+        result.appendChild(el("p", "", entry.method.toUpperCase() + " " + url));
+        button.disabled = true;
+
+        fetch(url, { method: entry.method.toUpperCase(), headers: headers, credentials: "same-origin" })
+            .then(function (response) {
+                return response.text().then(function (body) {
+                    result.appendChild(el("p", "status", response.status + " " + response.statusText));
+                    var headerLines = [];
+                    response.headers.forEach(function (value, name) {
+                        headerLines.push(name + ": " + value);
+                    });
+                    result.appendChild(el("pre", "", headerLines.sort().join("\n")));
+                    try {
+                        body = JSON.stringify(JSON.parse(body), null, 2);
+                    } catch (e) {
+                        // Not JSON; show the body as sent
+                    }
+                    result.appendChild(el("pre", "", body));
+                });
+            })
+            .catch(function (err) {
+                result.appendChild(el("p", "error", "Request failed: " + err.message));
+            })
+            .then(function () {
+                button.disabled = false;
+            });
+    }
+
+    function renderOperation(entry) {
+        var operation = entry.operation;
+        var details = el("details", "operation " + entry.method + (operation.deprecated ? " deprecated" : ""));
+        details.id = operation.operationId;
+
+        var summary = el("summary");
+        summary.appendChild(el("span", "method", entry.method.toUpperCase()));
+        summary.appendChild(el("span", "path", entry.path));
+        summary.appendChild(el("span", "summary", operation.summary + (operation.deprecated ? " (deprecated)" : "")));
+        details.appendChild(summary);
+
+        var body = el("div", "body");
+        if (operation.description) {
+            body.appendChild(el("p", "", operation.description));
+        }
+        if (operation.security) {
+            var scopes = [];
+            operation.security.forEach(function (requirement) {
+                Object.keys(requirement).forEach(function (name) {
+                    scopes = scopes.concat(requirement[name]);
+                });
+            });
+            if (scopes.length) {
+                body.appendChild(el("p", "", "Scope: " + scopes.join(", ")));
+            }
+        }
+
+        var inputs = [];
+        if (operation.parameters && operation.parameters.length) {
+            body.appendChild(el("h4", "", "Parameters"));
+            body.appendChild(renderParameters(operation.parameters, inputs));
+        }
+        var button = el("button", "", "Execute");
+        button.type = "button";
+        var result = el("div", "result");
+        button.addEventListener("click", function () {
+            execute(entry, inputs, button, result);
+        });
+        body.appendChild(button);
+        body.appendChild(result);
+
+        body.appendChild(el("h4", "", "Responses"));
+        body.appendChild(renderResponses(operation.responses));
+        details.appendChild(body);
+
+        details.addEventListener("toggle", function () {
+            if (details.open) {
+                history.replaceState(null, "", "#" + details.id);
+            }
+        });
+        return details;
+    }
+
+    function render(doc) {
+        renderInfo(doc);
+        var container = document.getElementById("docs");
+        container.textContent = "";
+        renderAuth(doc, container);
+
+        // Deprecated aliases repeat the versioned operations, so they are
+        // hidden unless asked for
+        var toggle = el("label", "toggle");
+        var showDeprecated = el("input");
+        showDeprecated.type = "checkbox";
+        toggle.appendChild(showDeprecated);
+        toggle.appendChild(document.createTextNode(" Show deprecated operations"));
+        container.appendChild(toggle);
+
+        var byTag = {};
+        operations(doc).forEach(function (entry) {
+            var tag = (entry.operation.tags && entry.operation.tags[0]) || "default";
+            (byTag[tag] = byTag[tag] || []).push(entry);
+        });
+        var tags = (doc.tags || []).slice();
+        Object.keys(byTag).forEach(function (name) {
+            if (!tags.some(function (tag) { return tag.name === name; })) {
+                tags.push({ name: name });
+            }
+        });
+
+        var deprecated = [];
+        tags.forEach(function (tag) {
+            if (!byTag[tag.name]) {
+                return;
+            }
+            var section = el("section", "tag");
+            var heading = el("h2", "", tag.name);
+            if (tag.description) {
+                heading.appendChild(el("small", "", tag.description));
+            }
+            section.appendChild(heading);
+            byTag[tag.name].forEach(function (entry) {
+                var node = renderOperation(entry);
+                if (entry.operation.deprecated) {
+                    node.hidden = true;
+                    deprecated.push(node);
+                }
+                section.appendChild(node);
+            });
+            container.appendChild(section);
+        });
+
+        showDeprecated.addEventListener("change", function () {
+            deprecated.forEach(function (node) {
+                node.hidden = !showDeprecated.checked;
+            });
+        });
+
+        // Open the operation linked from the URL fragment
+        var linked = location.hash && document.getElementById(location.hash.slice(1));
+        if (linked && linked.tagName === "DETAILS") {
+            linked.hidden = false;
+            linked.open = true;
+            linked.scrollIntoView();
+        }
+    }
+
+    fetch("/openapi.json")
+        .then(function (response) {
+            if (!response.ok) {
+                throw new Error(response.status + " " + response.statusText);
+            }
+            return response.json();
+        })
+        .then(render)
+        .catch(function (err) {
+            document.getElementById("docs").appendChild(el("p", "error", "Failed to load /openapi.json: " + err.message));
+        });
+})();
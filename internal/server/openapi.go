@@ -0,0 +1,547 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/config"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/handlers"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/models"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/openapi"
+)
+
+// WeatherDataDescriptions documents every WeatherData field by JSON path
+var WeatherDataDescriptions = map[string]string{
+	"name":                       "City name as reported by OpenWeatherMap",
+	"country":                    "ISO 3166 country code",
+	"timezone":                   "Offset from UTC in seconds",
+	"local_time":                 "Current time at the location (HH:MM:SS)",
+	"main":                       "Temperature, humidity and pressure",
+	"main.temp_kelvin":           "Temperature in kelvin",
+	"main.temp_celsius":          "Temperature in degrees Celsius",
+	"main.temp_fahrenheit":       "Temperature in degrees Fahrenheit",
+	"main.feels_like":            "Perceived temperature",
+	"main.feels_like.kelvin":     "Perceived temperature in kelvin",
+	"main.feels_like.celsius":    "Perceived temperature in degrees Celsius",
+	"main.feels_like.fahrenheit": "Perceived temperature in degrees Fahrenheit",
+	"main.temp_min":              "Minimum temperature currently observed in the area",
+	"main.temp_min.kelvin":       "Minimum temperature in kelvin",
+	"main.temp_min.celsius":      "Minimum temperature in degrees Celsius",
+	"main.temp_min.fahrenheit":   "Minimum temperature in degrees Fahrenheit",
+	"main.temp_max":              "Maximum temperature currently observed in the area",
+	"main.temp_max.kelvin":       "Maximum temperature in kelvin",
+	"main.temp_max.celsius":      "Maximum temperature in degrees Celsius",
+	"main.temp_max.fahrenheit":   "Maximum temperature in degrees Fahrenheit",
+	"main.humidity":              "Relative humidity in percent",
+	"main.pressure":              "Atmospheric pressure at sea level in hPa",
+	"wind":                       "Wind speed and direction",
+	"wind.speed_ms":              "Wind speed in metres per second",
+	"wind.speed_kmh":             "Wind speed in kilometres per hour",
+	"wind.speed_mph":             "Wind speed in miles per hour",
+	"wind.direction":             "Cardinal wind direction such as NE",
+	"wind.degrees":               "Wind direction in degrees",
+	"clouds":                     "Cloud cover",
+	"clouds.all":                 "Cloudiness in percent",
+	"weather":                    "Weather conditions, most significant first",
+	"weather.main":               "Condition group such as Clouds or Rain",
+	"weather.description":        "Condition within the group, e.g. overcast clouds, in the requested language",
+	"weather.icon":               "OpenWeatherMap icon ID",
+	"visibility_meters":          "Visibility in metres",
+	"dt":                         "Time of the observation (Unix seconds, UTC)",
+	"sunrise":                    "Sunrise (Unix seconds, UTC)",
+	"sunset":                     "Sunset (Unix seconds, UTC)",
+	"sunrise_time":               "Sunrise in local time (HH:MM:SS)",
+	"sunset_time":                "Sunset in local time (HH:MM:SS)",
+	"uv_index":                   "UV index",
+	"air_quality":                "Air quality label: Good, Fair, Moderate, Poor or Very Poor",
+	"aqi":                        "Air quality index from 1 (good) to 5 (very poor)",
+	"coordinates":                "Location of the weather station",
+	"coordinates.latitude":       "Latitude in degrees",
+	"coordinates.longitude":      "Longitude in degrees",
+	"last_updated":               "When the service fetched the data (RFC 3339)",
+	"cache_hit":                  "Whether the response was served from cache",
+}
+
+// SingleUnitDescriptions documents the SingleUnitWeatherData fields that
+// replace or add to those of WeatherData, by JSON path
+var SingleUnitDescriptions = map[string]string{
+	"units":           "Unit system of temperatures and wind speed: metric, imperial or standard",
+	"main":            "Temperature, humidity and pressure",
+	"main.temp":       "Temperature in the requested units",
+	"main.feels_like": "Perceived temperature in the requested units",
+	"main.temp_min":   "Minimum temperature currently observed in the area, in the requested units",
+	"main.temp_max":   "Maximum temperature currently observed in the area, in the requested units",
+	"main.humidity":   "Relative humidity in percent",
+	"main.pressure":   "Atmospheric pressure at sea level in hPa",
+	"wind":            "Wind speed and direction",
+	"wind.speed":      "Wind speed in metres per second, or miles per hour for imperial units",
+	"wind.direction":  "Cardinal wind direction such as NE",
+	"wind.degrees":    "Wind direction in degrees",
+}
+
+// singleUnitDescriptions returns the descriptions of every
+// SingleUnitWeatherData field
+func singleUnitDescriptions() map[string]string {
+	descriptions := make(map[string]string, len(WeatherDataDescriptions)+len(SingleUnitDescriptions))
+	for path, description := range WeatherDataDescriptions {
+		descriptions[path] = description
+	}
+	for path, description := range SingleUnitDescriptions {
+		descriptions[path] = description
+	}
+	return descriptions
+}
+
+// OpenAPI returns the OpenAPI 3 document describing every route
+func OpenAPI() *openapi.Document {
+	doc := &openapi.Document{
+		OpenAPI: openapi.Version,
+		Info: openapi.Info{
+			Title:   "Weather Microservice API",
+			Version: "1.0.0",
+			Description: "Current weather, UV index and air quality for any city or location, served from cache when possible. " +
+				"API routes are versioned under /v1; the unversioned aliases are deprecated. " +
+				"Protected routes need a JWT bearer token with the listed scope when authentication is enabled, " +
+				"and an X-API-Key header when multi-tenancy is enabled.",
+		},
+		Tags: []openapi.Tag{
+			{Name: "weather", Description: "Weather lookups"},
+			{Name: "cache", Description: "Cache administration"},
+			{Name: "operations", Description: "Health, metrics, configuration and documentation"},
+		},
+		Paths: map[string]openapi.PathItem{},
+		Components: openapi.Components{
+			Schemas: map[string]*openapi.Schema{
+				"WeatherData":           openapi.SchemaOf(reflect.TypeOf(models.WeatherData{}), WeatherDataDescriptions),
+				"SingleUnitWeatherData": openapi.SchemaOf(reflect.TypeOf(models.SingleUnitWeatherData{}), singleUnitDescriptions()),
+				"ErrorResponse":         errorResponseSchema(),
+				"HealthStatus":          healthSchema(),
+				"ReadinessStatus":       readinessSchema(),
+				"CacheEntry":            cacheEntrySchema(),
+				"CacheStats":            cacheStatsSchema(),
+				"StatusMessage":         statusMessageSchema(),
+				"Config":                openapi.SchemaOf(reflect.TypeOf(config.Config{}), nil),
+			},
+			SecuritySchemes: map[string]openapi.SecurityScheme{
+				"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT", Description: "JWT with the scope listed on the operation"},
+				"apiKeyAuth": {Type: "apiKey", Name: "X-API-Key", In: "header", Description: "Tenant credential"},
+			},
+		},
+	}
+
+	for path, methods := range operationalOperations() {
+		doc.Paths[path] = methods
+	}
+
+	for _, version := range apiVersions {
+		operations := version.Operations()
+		for _, route := range version.Routes(&handlers.Handler{}) {
+			key := route.Method + " " + route.Path
+			operation, documented := operations[key]
+			if !documented {
+				continue
+			}
+			versioned := *operation
+			versioned.OperationID = version.Name + operation.OperationID
+			versioned.Responses = withVersionResponses(operation.Responses, route.Scope)
+			versioned.Parameters = append([]openapi.Parameter{acceptParameter()}, operation.Parameters...)
+			if route.Scope != "" {
+				versioned.Security = security(route.Scope)
+				versioned.Description = strings.TrimSpace(versioned.Description + fmt.Sprintf(" Requires the `%s` scope when authentication is enabled.", route.Scope))
+			}
+			addOperation(doc, "/"+version.Name+route.Path, route.Method, &versioned)
+
+			if version.Name == DefaultVersion {
+				legacy := versioned
+				legacy.OperationID = "legacy" + operation.OperationID
+				legacy.Deprecated = true
+				legacy.Description = strings.TrimSpace(fmt.Sprintf("Deprecated alias of /%s%s. ", version.Name, route.Path) + versioned.Description)
+				legacy.Responses = withDeprecationHeaders(versioned.Responses)
+				addOperation(doc, route.Path, route.Method, &legacy)
+			}
+		}
+	}
+	return doc
+}
+
+// OpenAPIHandler serves the OpenAPI document as JSON
+func OpenAPIHandler() http.HandlerFunc {
+	body, err := json.MarshalIndent(OpenAPI(), "", "  ")
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to encode OpenAPI document: %v", err))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}
+}
+
+func addOperation(doc *openapi.Document, path, method string, operation *openapi.Operation) {
+	item, found := doc.Paths[path]
+	if !found {
+		item = openapi.PathItem{}
+		doc.Paths[path] = item
+	}
+	item[strings.ToLower(method)] = operation
+}
+
+// operationalOperations documents the unversioned operational routes
+func operationalOperations() map[string]openapi.PathItem {
+	return map[string]openapi.PathItem{
+		"/": {"get": {
+			OperationID: "docs",
+			Summary:     "Interactive API documentation",
+			Description: "Renders this document and lets each operation be tried out. The page's script and styles are served from /docs/ on this origin.",
+			Tags:        []string{"operations"},
+			Responses: map[string]openapi.Response{
+				"200": {Description: "Documentation page", Content: map[string]openapi.MediaType{"text/html": {Schema: &openapi.Schema{Type: "string"}}}},
+			},
+		}},
+		"/docs/{file}": {"get": {
+			OperationID: "docsAsset",
+			Summary:     "Documentation page asset",
+			Tags:        []string{"operations"},
+			Parameters: []openapi.Parameter{{
+				Name:        "file",
+				In:          "path",
+				Required:    true,
+				Description: "Asset of the documentation page",
+				Schema:      &openapi.Schema{Type: "string", Enum: []string{"docs.js", "docs.css"}},
+			}},
+			Responses: map[string]openapi.Response{
+				"200": {Description: "Script or stylesheet"},
+				"404": {Description: "No such asset"},
+			},
+		}},
+		"/openapi.json": {"get": {
+			OperationID: "openAPI",
+			Summary:     "This OpenAPI document",
+			Tags:        []string{"operations"},
+			Responses: map[string]openapi.Response{
+				"200": jsonResponse("OpenAPI 3 document", &openapi.Schema{Type: "object"}),
+			},
+		}},
+		"/health": {"get": {
+			OperationID: "health",
+			Summary:     "Service health",
+			Tags:        []string{"operations"},
+			Responses:   map[string]openapi.Response{"200": jsonResponse("Service is healthy", openapi.Ref("HealthStatus"))},
+		}},
+		"/readiness": {"get": {
+			OperationID: "readiness",
+			Summary:     "Readiness probe",
+			Tags:        []string{"operations"},
+			Responses:   map[string]openapi.Response{"200": jsonResponse("Service is ready for traffic", openapi.Ref("ReadinessStatus"))},
+		}},
+		"/metrics": {"get": {
+			OperationID: "metrics",
+			Summary:     "Performance metrics",
+			Description: "Request counts, response times, cache hit rates, top cities and per-tenant usage.",
+			Tags:        []string{"operations"},
+			Responses: map[string]openapi.Response{
+				"200": jsonResponse("Metrics", &openapi.Schema{Type: "object", AdditionalProperties: true}),
+				"429": errorResponse("Rate limit exceeded"),
+			},
+		}},
+	}
+}
+
+// v1Operations documents the routes returned by v1Routes, keyed by
+// "METHOD path"
+func v1Operations() map[string]*openapi.Operation {
+	cacheKey := openapi.Parameter{
+		Name:        "key",
+		In:          "path",
+		Required:    true,
+		Description: "Cache key as listed by GET /cache, e.g. london, london;units=imperial;lang=de, grid:51.51,-0.13 or uv:grid:51.51,-0.13",
+		Schema:      &openapi.Schema{Type: "string"},
+	}
+
+	return map[string]*openapi.Operation{
+		"GET /weather": {
+			OperationID: "GetWeather",
+			Summary:     "Current weather for a city or location",
+			Description: "Pass either city or both lat and lon. Coordinates are snapped to a grid so nearby lookups share a cache entry. " +
+				"Each combination of units and lang is fetched and cached separately. " +
+				"Responses carry ETag, Last-Modified and Cache-Control headers and honour If-None-Match and If-Modified-Since.",
+			Tags: []string{"weather"},
+			Parameters: []openapi.Parameter{
+				{Name: "city", In: "query", Description: "City name, matched case-insensitively", Schema: &openapi.Schema{Type: "string", Example: "London"}},
+				{Name: "lat", In: "query", Description: "Latitude, used with lon", Schema: &openapi.Schema{Type: "number", Format: "double", Minimum: float(-90), Maximum: float(90)}},
+				{Name: "lon", In: "query", Description: "Longitude, used with lat", Schema: &openapi.Schema{Type: "number", Format: "double", Minimum: float(-180), Maximum: float(180)}},
+				{Name: "units", In: "query", Description: "Unit system requested from upstream. Defaults to metric", Schema: &openapi.Schema{Type: "string", Enum: []string{"metric", "imperial", "standard"}}},
+				{Name: "lang", In: "query", Description: "Language of condition descriptions as an OpenWeatherMap or ISO 639-1 code, e.g. de, fr, cs or pt_br. Defaults to English", Schema: &openapi.Schema{Type: "string", Example: "de"}},
+				{Name: "single_unit", In: "query", Description: "Return temperatures and wind speed in the requested units only, as SingleUnitWeatherData", Schema: &openapi.Schema{Type: "boolean"}},
+				{Name: "If-None-Match", In: "header", Description: "ETag of a previously received response", Schema: &openapi.Schema{Type: "string"}},
+				{Name: "If-Modified-Since", In: "header", Description: "Last-Modified of a previously received response", Schema: &openapi.Schema{Type: "string"}},
+			},
+			Responses: map[string]openapi.Response{
+				"200": {
+					Description: "Current weather",
+					Headers: map[string]openapi.Header{
+						"ETag":          {Description: "Validator for conditional requests", Schema: &openapi.Schema{Type: "string"}},
+						"Last-Modified": {Description: "Time of the observation", Schema: &openapi.Schema{Type: "string"}},
+						"Cache-Control": {Description: "How long the response may be cached", Schema: &openapi.Schema{Type: "string"}},
+					},
+					Content: map[string]openapi.MediaType{"application/json": {Schema: &openapi.Schema{
+						OneOf: []*openapi.Schema{openapi.Ref("WeatherData"), openapi.Ref("SingleUnitWeatherData")},
+					}}},
+				},
+				"304": {Description: "Not modified since the cached copy"},
+				"400": errorResponse("Missing city, invalid coordinates, or unsupported units, lang or single_unit"),
+				"404": errorResponse("City not found upstream"),
+				"500": errorResponse("Upstream request failed"),
+			},
+		},
+		"GET /config": {
+			OperationID: "GetConfig",
+			Summary:     "Active configuration",
+			Description: "Secrets are redacted.",
+			Tags:        []string{"operations"},
+			Responses: map[string]openapi.Response{
+				"200": jsonResponse("Active configuration", openapi.Ref("Config")),
+				"404": errorResponse("Configuration is not available"),
+			},
+		},
+		"GET /cache": {
+			OperationID: "GetCacheStats",
+			Summary:     "Cache statistics and entries",
+			Description: "A tenant with a cache namespace only sees its own entries; the hit and miss counters and backend statistics, which cover every namespace, are left out.",
+			Tags:        []string{"cache"},
+			Responses:   map[string]openapi.Response{"200": jsonResponse("Cache statistics", openapi.Ref("CacheStats"))},
+		},
+		"DELETE /cache": {
+			OperationID: "InvalidateCache",
+			Summary:     "Remove every entry matching a prefix or glob pattern",
+			Tags:        []string{"cache"},
+			Parameters: []openapi.Parameter{
+				{Name: "prefix", In: "query", Description: "Remove keys starting with this prefix, e.g. acme:", Schema: &openapi.Schema{Type: "string"}},
+				{Name: "pattern", In: "query", Description: "Remove keys matching this glob, e.g. uv:*", Schema: &openapi.Schema{Type: "string"}},
+			},
+			Responses: map[string]openapi.Response{
+				"200": jsonResponse("Entries removed", &openapi.Schema{
+					Type: "object",
+					Properties: map[string]*openapi.Schema{
+						"status":  {Type: "string", Example: "success"},
+						"deleted": {Type: "integer", Format: "int32", Description: "Number of entries removed"},
+						"time":    {Type: "string", Format: "date-time"},
+					},
+				}),
+				"400": errorResponse("Neither or both of prefix and pattern given, or invalid pattern"),
+				"500": errorResponse("Cache backend failed"),
+			},
+		},
+		"POST /cache/clear": {
+			OperationID: "ClearCache",
+			Summary:     "Clear the cache",
+			Description: "A tenant with a cache namespace only clears its own entries.",
+			Tags:        []string{"cache"},
+			Parameters: []openapi.Parameter{
+				{Name: "type", In: "query", Description: "Clear only cached \"not found\" results", Schema: &openapi.Schema{Type: "string", Enum: []string{"negative"}}},
+			},
+			Responses: map[string]openapi.Response{
+				"200": jsonResponse("Cache cleared", openapi.Ref("StatusMessage")),
+				"400": errorResponse("Unknown type"),
+				"500": errorResponse("Cache backend failed"),
+			},
+		},
+		"GET /cache/{key}": {
+			OperationID: "GetCacheEntry",
+			Summary:     "A single cache entry",
+			Tags:        []string{"cache"},
+			Parameters:  []openapi.Parameter{cacheKey},
+			Responses: map[string]openapi.Response{
+				"200": jsonResponse("Cache entry", openapi.Ref("CacheEntry")),
+				"404": errorResponse("No entry under this key"),
+			},
+		},
+		"DELETE /cache/{key}": {
+			OperationID: "DeleteCacheEntry",
+			Summary:     "Remove a single cache entry",
+			Tags:        []string{"cache"},
+			Parameters:  []openapi.Parameter{cacheKey},
+			Responses: map[string]openapi.Response{
+				"200": jsonResponse("Entry removed", openapi.Ref("StatusMessage")),
+				"404": errorResponse("No entry under this key"),
+				"500": errorResponse("Cache backend failed"),
+			},
+		},
+		"POST /cache/{key}/refresh": {
+			OperationID: "RefreshCacheEntry",
+			Summary:     "Fetch a cache entry again from upstream",
+			Tags:        []string{"cache"},
+			Parameters:  []openapi.Parameter{cacheKey},
+			Responses: map[string]openapi.Response{
+				"200": jsonResponse("Refreshed entry", &openapi.Schema{
+					Type: "object",
+					Properties: map[string]*openapi.Schema{
+						"status":     {Type: "string", Example: "success"},
+						"key":        {Type: "string"},
+						"expires_at": {Type: "string", Format: "date-time"},
+						"data":       openapi.Ref("WeatherData"),
+					},
+				}),
+				"400": errorResponse("Key does not name a refreshable entry"),
+				"404": errorResponse("City not found upstream"),
+				"500": errorResponse("Upstream request failed; the cached entry is kept"),
+			},
+		},
+	}
+}
+
+// withVersionResponses adds the responses every versioned route can return
+func withVersionResponses(responses map[string]openapi.Response, scope string) map[string]openapi.Response {
+	merged := make(map[string]openapi.Response, len(responses)+4)
+	for code, response := range responses {
+		merged[code] = response
+	}
+	if scope != "" {
+		merged["401"] = errorResponse("Missing or invalid credentials")
+		merged["403"] = errorResponse("Token lacks the required scope, or the tenant may not call this endpoint")
+	}
+	merged["406"] = errorResponse("Accept asks for an unsupported API version")
+	merged["429"] = errorResponse("Rate limit exceeded")
+	return merged
+}
+
+// withDeprecationHeaders adds the headers sent by the unversioned aliases
+func withDeprecationHeaders(responses map[string]openapi.Response) map[string]openapi.Response {
+	merged := make(map[string]openapi.Response, len(responses))
+	for code, response := range responses {
+		headers := map[string]openapi.Header{
+			"Deprecation": {Description: "When the unversioned path was deprecated, as @<unix seconds>", Schema: &openapi.Schema{Type: "string"}},
+			"Sunset":      {Description: "When the unversioned path will be removed (HTTP date)", Schema: &openapi.Schema{Type: "string"}},
+			"Link":        {Description: "Successor version of the path", Schema: &openapi.Schema{Type: "string"}},
+		}
+		for name, header := range response.Headers {
+			headers[name] = header
+		}
+		response.Headers = headers
+		merged[code] = response
+	}
+	return merged
+}
+
+func acceptParameter() openapi.Parameter {
+	return openapi.Parameter{
+		Name:        "Accept",
+		In:          "header",
+		Description: "Optionally select the API version, e.g. application/vnd.weather.v1+json or application/json; version=1",
+		Schema:      &openapi.Schema{Type: "string"},
+	}
+}
+
+// security allows a bearer token with scope, a tenant API key, or no
+// credentials when authentication and multi-tenancy are disabled
+func security(scope string) []map[string][]string {
+	return []map[string][]string{
+		{"bearerAuth": {scope}},
+		{"apiKeyAuth": {}},
+		{},
+	}
+}
+
+func jsonResponse(description string, schema *openapi.Schema) openapi.Response {
+	return openapi.Response{
+		Description: description,
+		Content:     map[string]openapi.MediaType{"application/json": {Schema: schema}},
+	}
+}
+
+func errorResponse(description string) openapi.Response {
+	return jsonResponse(description, openapi.Ref("ErrorResponse"))
+}
+
+func float(v float64) *float64 {
+	return &v
+}
+
+func errorResponseSchema() *openapi.Schema {
+	schema := openapi.SchemaOf(reflect.TypeOf(models.ErrorResponse{}), map[string]string{
+		"error":   "What went wrong",
+		"message": "Human-readable detail, usually the same as error",
+		"code":    "HTTP status code",
+	})
+	schema.Required = []string{"error", "code"}
+	return schema
+}
+
+func healthSchema() *openapi.Schema {
+	return &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"status":        {Type: "string", Example: "healthy"},
+			"timestamp":     {Type: "string", Format: "date-time"},
+			"cache_entries": {Type: "integer", Format: "int32"},
+			"service":       {Type: "string"},
+			"version":       {Type: "string"},
+		},
+	}
+}
+
+func readinessSchema() *openapi.Schema {
+	return &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"status":    {Type: "string", Example: "ready"},
+			"timestamp": {Type: "string", Format: "date-time"},
+			"checks":    {Type: "object", AdditionalProperties: &openapi.Schema{Type: "boolean"}},
+		},
+	}
+}
+
+func cacheEntrySchema() *openapi.Schema {
+	return &openapi.Schema{
+		Type:        "object",
+		Description: "Negative entries record a city unknown upstream and carry only key and negative",
+		Properties: map[string]*openapi.Schema{
+			"key":                {Type: "string"},
+			"negative":           {Type: "boolean"},
+			"expires_at":         {Type: "string", Format: "date-time"},
+			"expires_in_seconds": {Type: "integer", Format: "int32"},
+			"stored_at":          {Type: "string", Format: "date-time"},
+			"age_seconds":        {Type: "integer", Format: "int32"},
+			"data":               openapi.Ref("WeatherData"),
+		},
+	}
+}
+
+func cacheStatsSchema() *openapi.Schema {
+	return &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"total_entries":           {Type: "integer", Format: "int32", Description: "Number of cached keys, including those left out of entries"},
+			"hit_count":               {Type: "integer", Format: "int64", Description: "Shared by every namespace, so left out for tenants with a cache namespace"},
+			"miss_count":              {Type: "integer", Format: "int64", Description: "Shared by every namespace, so left out for tenants with a cache namespace"},
+			"hit_rate":                {Type: "number", Format: "double", Description: "Shared by every namespace, so left out for tenants with a cache namespace"},
+			"cache_duration":          {Type: "string", Example: "10m0s"},
+			"entries":                 {Type: "object", Description: "Expiry time per key, for at most 1000 keys", AdditionalProperties: &openapi.Schema{Type: "string"}},
+			"entries_truncated":       {Type: "boolean", Description: "Whether keys were left out of entries"},
+			"negative_entries":        {Type: "integer", Format: "int32"},
+			"negative_hit_count":      {Type: "integer", Format: "int64", Description: "Shared by every namespace, so left out for tenants with a cache namespace"},
+			"negative_cache_duration": {Type: "string", Example: "2m0s"},
+			"namespace":               {Type: "string", Description: "Cache namespace the entries are limited to, for tenants that have one", Example: "acme:"},
+			"backend": {
+				Type:                 "object",
+				Description:          "Statistics of the cache backend, such as size limits, evictions or per-tier hits. Left out for tenants with a cache namespace",
+				AdditionalProperties: true,
+			},
+		},
+	}
+}
+
+func statusMessageSchema() *openapi.Schema {
+	return &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"status":  {Type: "string", Example: "success"},
+			"message": {Type: "string"},
+			"key":     {Type: "string"},
+			"time":    {Type: "string", Format: "date-time"},
+		},
+	}
+}
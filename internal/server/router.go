@@ -0,0 +1,96 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/auth"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/handlers"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/middleware"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/tenant"
+	"github.com/gorilla/mux"
+)
+
+// Options are the components the HTTP API is built from
+type Options struct {
+	Handler *handlers.Handler
+
+	// Verifier checks bearer tokens. Nil disables authentication.
+	Verifier *auth.Verifier
+
+	// Registry resolves tenants. Nil serves every request as the default tenant.
+	Registry *tenant.Registry
+
+	// RateLimiter limits requests per client. Nil disables the global limit.
+	RateLimiter *middleware.RateLimiter
+
+	// LegacyDeprecation and LegacySunset are announced on unversioned API
+	// paths. Zero values use DefaultLegacyDeprecation and DefaultLegacySunset.
+	LegacyDeprecation time.Time
+	LegacySunset      time.Time
+}
+
+// NewRouter registers every route of the API with its middleware. API routes
+// are mounted under a version prefix such as /v1; the unversioned paths are
+// deprecated aliases. CORS is applied by the caller around the returned
+// router, so preflight requests are answered before route matching.
+func NewRouter(opts Options) *mux.Router {
+	registry := opts.Registry
+	if registry == nil {
+		registry = tenant.NewRegistry(nil, &tenant.Tenant{ID: tenant.DefaultID})
+	}
+	handler := opts.Handler
+
+	// protect wraps API handlers with authentication and tenant resolution
+	protect := func(scope string, h http.HandlerFunc) http.Handler {
+		return middleware.AuthMiddleware(opts.Verifier, scope)(middleware.TenantMiddleware(registry)(h))
+	}
+
+	// Setup router with middleware
+	router := mux.NewRouter()
+	router.Use(middleware.LoggingMiddleware)
+	router.Use(middleware.CompressionMiddleware(middleware.DefaultCompressionMinSize))
+	router.Use(middleware.RecoveryMiddleware)
+	if opts.RateLimiter != nil {
+		router.Use(opts.RateLimiter.Middleware)
+	}
+
+	// Operational routes are not versioned
+	router.HandleFunc("/", DocsHandler()).Methods("GET")
+	router.Handle("/docs/{file}", DocsAssetsHandler()).Methods("GET")
+	router.HandleFunc("/health", handler.HealthHandler).Methods("GET")
+	router.HandleFunc("/readiness", handler.ReadinessHandler).Methods("GET")
+	router.HandleFunc("/metrics", handler.MetricsHandler).Methods("GET")
+	router.HandleFunc("/openapi.json", OpenAPIHandler()).Methods("GET")
+
+	// Each API version is mounted under its own prefix
+	versions := make(map[string]*mux.Router, len(apiVersions))
+	legacySegments := make(map[string]bool)
+	for _, version := range apiVersions {
+		sub := router.PathPrefix("/" + version.Name).Subrouter()
+		sub.Use(versionMiddleware(version.Name))
+		for _, route := range version.Routes(handler) {
+			h := http.Handler(route.Handler)
+			if route.Scope != "" {
+				h = protect(route.Scope, route.Handler)
+			}
+			sub.Handle(route.Path, h).Methods(route.Method)
+			if version.Name == DefaultVersion {
+				legacySegments[firstSegment(route.Path)] = true
+			}
+		}
+		versions[version.Name] = sub
+	}
+
+	// Unversioned API paths are deprecated aliases
+	deprecation, sunset := opts.LegacyDeprecation, opts.LegacySunset
+	if deprecation.IsZero() {
+		deprecation = DefaultLegacyDeprecation
+	}
+	if sunset.IsZero() {
+		sunset = DefaultLegacySunset
+	}
+	router.MatcherFunc(legacyMatcher(legacySegments)).Handler(legacyHandler(versions, deprecation, sunset))
+
+	return router
+}
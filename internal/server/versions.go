@@ -0,0 +1,148 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/auth"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/handlers"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/models"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/openapi"
+	"github.com/gorilla/mux"
+)
+
+// DefaultVersion serves unversioned requests that do not ask for a version
+const DefaultVersion = "v1"
+
+// Default deprecation schedule of the unversioned API paths
+var (
+	DefaultLegacyDeprecation = time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)
+	DefaultLegacySunset      = time.Date(2027, time.May, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// Route is an API endpoint mounted under a version prefix. Routes with a
+// Scope require authentication and tenant resolution.
+type Route struct {
+	Method  string
+	Path    string
+	Scope   string
+	Handler http.HandlerFunc
+}
+
+// APIVersion is a version of the API, the routes it serves and their
+// OpenAPI documentation keyed by "METHOD path"
+type APIVersion struct {
+	Name       string
+	Routes     func(h *handlers.Handler) []Route
+	Operations func() map[string]*openapi.Operation
+}
+
+// apiVersions lists every mounted API version, oldest first. A new version
+// with a different response schema is added here with its own routes.
+var apiVersions = []APIVersion{
+	{Name: "v1", Routes: v1Routes, Operations: v1Operations},
+}
+
+// v1Routes are the routes of the original API
+func v1Routes(h *handlers.Handler) []Route {
+	return []Route{
+		{"GET", "/weather", auth.ScopeWeatherRead, h.WeatherHandler},
+		{"GET", "/config", auth.ScopeAdmin, h.ConfigHandler},
+		{"GET", "/cache", auth.ScopeAdmin, h.CacheHandler},
+		{"DELETE", "/cache", auth.ScopeAdmin, h.CacheInvalidateHandler},
+		{"POST", "/cache/clear", auth.ScopeAdmin, h.CacheClearHandler},
+		{"GET", "/cache/{key}", auth.ScopeAdmin, h.CacheEntryHandler},
+		{"DELETE", "/cache/{key}", auth.ScopeAdmin, h.CacheDeleteHandler},
+		{"POST", "/cache/{key}/refresh", auth.ScopeAdmin, h.CacheRefreshHandler},
+	}
+}
+
+// legacyMatcher matches unversioned paths whose first segment is one of
+// segments, e.g. "weather" for /weather
+func legacyMatcher(segments map[string]bool) mux.MatcherFunc {
+	return func(r *http.Request, _ *mux.RouteMatch) bool {
+		return segments[firstSegment(r.URL.Path)]
+	}
+}
+
+func firstSegment(path string) string {
+	segment := strings.TrimPrefix(path, "/")
+	if i := strings.Index(segment, "/"); i >= 0 {
+		segment = segment[:i]
+	}
+	return segment
+}
+
+// legacyHandler serves an unversioned path from the version selected by the
+// Accept header, or DefaultVersion, and announces its deprecation
+func legacyHandler(versions map[string]*mux.Router, deprecation, sunset time.Time) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version := RequestedVersion(r)
+		if version == "" {
+			version = DefaultVersion
+		}
+		sub, found := versions[version]
+		if !found {
+			writeError(w, http.StatusNotAcceptable, fmt.Sprintf("API version '%s' is not supported", version))
+			return
+		}
+
+		successor := "/" + version + r.URL.Path
+		w.Header().Set("Deprecation", fmt.Sprintf("@%d", deprecation.Unix()))
+		w.Header().Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+		w.Header().Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, successor))
+		w.Header().Add("Vary", "Accept")
+
+		versioned := r.Clone(r.Context())
+		versioned.URL.Path = successor
+		versioned.URL.RawPath = ""
+		sub.ServeHTTP(w, versioned)
+	})
+}
+
+// versionMiddleware labels responses with the API version and rejects an
+// Accept header asking for a different one
+func versionMiddleware(version string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if requested := RequestedVersion(r); requested != "" && requested != version {
+				writeError(w, http.StatusNotAcceptable, fmt.Sprintf("Accept asks for API version '%s' but the path is /%s", requested, version))
+				return
+			}
+			w.Header().Set("API-Version", version)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequestedVersion returns the API version asked for in the Accept header,
+// either as a media type such as "application/vnd.weather.v2+json" or a
+// parameter such as "application/json; version=2". It returns "" when the
+// header does not ask for a version.
+func RequestedVersion(r *http.Request) string {
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err != nil {
+			continue
+		}
+		if version := strings.TrimPrefix(params["version"], "v"); version != "" {
+			return "v" + version
+		}
+		if rest, ok := strings.CutPrefix(mediaType, "application/vnd.weather."); ok {
+			if version, _, _ := strings.Cut(rest, "+"); strings.HasPrefix(version, "v") {
+				return version
+			}
+		}
+	}
+	return ""
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(models.ErrorResponse{Error: message, Message: message, Code: code})
+}
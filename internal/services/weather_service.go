@@ -1,22 +1,48 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 	"github.com/Vivek-Prakash1307/weather-Microservices/api/openweathermap"
 	"github.com/Vivek-Prakash1307/weather-Microservices/internal/cache"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/location"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/logging"
 	"github.com/Vivek-Prakash1307/weather-Microservices/internal/metrics"
 	"github.com/Vivek-Prakash1307/weather-Microservices/internal/models"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/tenant"
 	"github.com/Vivek-Prakash1307/weather-Microservices/pkg/utils"
 )
 
-// WeatherService handles weather-related business logic
+// ErrInvalidCacheKey is returned when a cache key cannot be mapped back to
+// an upstream lookup
+var ErrInvalidCacheKey = errors.New("invalid cache key")
+
+const (
+	// DefaultUVIndexTTL is how long a UV index reading is cached
+	DefaultUVIndexTTL = time.Hour
+	// DefaultAirQualityTTL is how long an air quality reading is cached
+	DefaultAirQualityTTL = 30 * time.Minute
+	// FailedReadingTTL is how long a failed UV index or air quality lookup is
+	// remembered, so an upstream outage does not cost two calls per request
+	FailedReadingTTL = time.Minute
+)
+
+// WeatherService handles weather-related business logic. The upstream client
+// and TTLs can be swapped by a config reload while requests are in flight.
 type WeatherService struct {
-	weatherClient  *openweathermap.Client
+	weatherClient  atomic.Pointer[openweathermap.Client]
 	cacheManager   *cache.CacheManager
 	metricsManager *metrics.MetricsManager
+	tenantClients  map[string]*openweathermap.Client
+	mu             sync.Mutex
+	uvIndexTTL     atomic.Int64
+	airQualityTTL  atomic.Int64
+	keyer          location.Keyer
 }
 
 // NewWeatherService creates a new weather service
@@ -25,103 +51,391 @@ func NewWeatherService(
 	cacheManager *cache.CacheManager,
 	metricsManager *metrics.MetricsManager,
 ) *WeatherService {
-	return &WeatherService{
-		weatherClient:  weatherClient,
+	ws := &WeatherService{
 		cacheManager:   cacheManager,
 		metricsManager: metricsManager,
+		tenantClients:  make(map[string]*openweathermap.Client),
+		keyer:          location.DefaultKeyer(),
 	}
+	ws.weatherClient.Store(weatherClient)
+	ws.SetDataTTLs(DefaultUVIndexTTL, DefaultAirQualityTTL)
+	return ws
+}
+
+// SetClient replaces the default upstream client, for example after the API
+// key or base URL changed. Clients for tenants with their own key are rebuilt
+// on next use so they pick up the new base URL.
+func (ws *WeatherService) SetClient(client *openweathermap.Client) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	ws.weatherClient.Store(client)
+	ws.tenantClients = make(map[string]*openweathermap.Client)
 }
 
-// GetWeatherData fetches weather data for a city
+// SetDataTTLs sets how long UV index and air quality readings are cached.
+// They change more slowly than current weather, so they are cached under
+// their own keys and only refetched once their own TTL runs out.
+func (ws *WeatherService) SetDataTTLs(uvIndex, airQuality time.Duration) {
+	ws.uvIndexTTL.Store(int64(uvIndex))
+	ws.airQualityTTL.Store(int64(airQuality))
+}
+
+// SetLocationKeyer sets how coordinates are snapped into shared cache keys
+func (ws *WeatherService) SetLocationKeyer(keyer location.Keyer) {
+	ws.keyer = keyer
+}
+
+// GetWeatherData fetches weather data for a city in metric units
 func (ws *WeatherService) GetWeatherData(city string) (*models.WeatherData, error) {
+	return ws.GetWeatherDataForTenant(nil, city, openweathermap.Options{})
+}
+
+// GetWeatherDataForTenant fetches weather data for a city using the tenant's
+// upstream API key and cache namespace. A nil tenant uses the service defaults.
+// Each unit system and language is cached separately, while a city upstream
+// does not know is remembered once for all of them.
+func (ws *WeatherService) GetWeatherDataForTenant(t *tenant.Tenant, city string, opts openweathermap.Options) (*models.WeatherData, error) {
+	return ws.cityWeather(t, city, opts, false)
+}
+
+// cityWeather looks up a city. With refresh set the cached and negative
+// entries are ignored and replaced only once upstream answers.
+func (ws *WeatherService) cityWeather(t *tenant.Tenant, city string, opts openweathermap.Options, refresh bool) (*models.WeatherData, error) {
 	startTime := time.Now()
-	city = strings.ToLower(strings.TrimSpace(city))
+	city = location.CanonicalCity(city)
 
 	if city == "" {
 		return nil, fmt.Errorf("city name cannot be empty")
 	}
+	// No city has a ';' in its name, and allowing one would let a lookup
+	// collide with the cache key of another city's options
+	if strings.Contains(city, optionsSeparator) {
+		return nil, &openweathermap.NotFoundError{City: city}
+	}
 
 	// Record city request
 	ws.metricsManager.RecordCityRequest(city)
 
 	// Check cache first
-	if cachedData, found := ws.cacheManager.Get(city); found {
+	notFoundKey := t.CacheKey(city)
+	cacheKey := t.CacheKey(optionsKey(city, opts))
+	if !refresh && ws.cacheManager.IsNotFound(notFoundKey) {
+		err := &openweathermap.NotFoundError{City: city}
+		duration := time.Since(startTime)
+		ws.metricsManager.RecordNegativeHit(duration)
+		ws.metricsManager.RecordTenantNegativeHit(tenantID(t))
+		if logging.Enabled(logging.Info) {
+			log.Printf("🚫 Negative cache hit for city: %s (took %v)", city, duration)
+		}
+		return nil, err
+	}
+
+	return ws.getWeather(t, "city: "+city, cacheKey, startTime, opts, refresh, func(client *openweathermap.Client) (*models.OpenWeatherResponse, error) {
+		apiResponse, err := client.GetWeather(city, opts)
+		var notFound *openweathermap.NotFoundError
+		if errors.As(err, &notFound) {
+			ws.cacheManager.SetNotFound(notFoundKey)
+		} else if err == nil && refresh {
+			ws.cacheManager.ForgetNotFound(notFoundKey)
+		}
+		return apiResponse, err
+	})
+}
+
+// GetWeatherDataByCoordsForTenant fetches weather data for a location. The
+// coordinates are snapped to the configured grid first, so nearby users share
+// one cache entry and one upstream lookup.
+func (ws *WeatherService) GetWeatherDataByCoordsForTenant(t *tenant.Tenant, lat, lon float64, opts openweathermap.Options) (*models.WeatherData, error) {
+	return ws.coordsWeather(t, lat, lon, opts, false)
+}
+
+// coordsWeather looks up a location, bypassing the cached entry when refresh
+// is set
+func (ws *WeatherService) coordsWeather(t *tenant.Tenant, lat, lon float64, opts openweathermap.Options, refresh bool) (*models.WeatherData, error) {
+	startTime := time.Now()
+	if !location.ValidCoordinates(lat, lon) {
+		return nil, fmt.Errorf("coordinates out of range: lat must be within [-90, 90] and lon within [-180, 180]")
+	}
+
+	lat, lon = ws.keyer.Snap(lat, lon)
+	cacheKey := t.CacheKey(optionsKey(ws.keyer.Key(lat, lon), opts))
+	label := fmt.Sprintf("coordinates: %.4f,%.4f", lat, lon)
+
+	return ws.getWeather(t, label, cacheKey, startTime, opts, refresh, func(client *openweathermap.Client) (*models.OpenWeatherResponse, error) {
+		return client.GetWeatherByCoords(lat, lon, opts)
+	})
+}
+
+// getWeather serves weather from cache or fetches it with fetch, then fills
+// in the UV index and air quality readings. refresh skips the cache lookup,
+// so the cached entry is only replaced once fetch succeeds.
+func (ws *WeatherService) getWeather(
+	t *tenant.Tenant,
+	label, cacheKey string,
+	startTime time.Time,
+	opts openweathermap.Options,
+	refresh bool,
+	fetch func(client *openweathermap.Client) (*models.OpenWeatherResponse, error),
+) (*models.WeatherData, error) {
+	client := ws.clientFor(t)
+
+	if cachedData, found := ws.cacheManager.Get(cacheKey); found && !refresh {
+		upstreamCalls := ws.addReadings(t, client, &cachedData)
 		cachedData.CacheHit = true
 		duration := time.Since(startTime)
 		ws.metricsManager.RecordRequest(duration, true, nil)
-		log.Printf("✅ Cache hit for city: %s (took %v)", city, duration)
+		ws.recordTenantRequest(t, true, upstreamCalls, nil)
+		if logging.Enabled(logging.Info) {
+			log.Printf("✅ Cache hit for %s (took %v)", label, duration)
+		}
 		return &cachedData, nil
 	}
 
-	log.Printf("🔄 Cache miss for city: %s, fetching from API...", city)
+	if logging.Enabled(logging.Info) {
+		log.Printf("🔄 Cache miss for %s, fetching from API...", label)
+	}
 
 	// Fetch from API
-	apiResponse, err := ws.weatherClient.GetWeather(city)
+	apiResponse, err := fetch(client)
 	if err != nil {
 		duration := time.Since(startTime)
 		ws.metricsManager.RecordRequest(duration, false, err)
+		ws.recordTenantRequest(t, false, 1, err)
 		return nil, err
 	}
 
 	// Transform API response to our weather data model
-	weatherData := ws.transformWeatherData(apiResponse)
+	weatherData := ws.transformWeatherData(apiResponse, opts.Units)
+	weatherData.CacheHit = false
+	weatherData.LastUpdated = time.Now().Format("2006-01-02 15:04:05 MST")
 
-	// Fetch UV Index and Air Quality in parallel
-	uvChan := make(chan float64, 1)
-	aqiChan := make(chan struct {
-		aqi     int
-		quality string
-	}, 1)
+	// The weather is cached on its own for the full weather TTL; the readings
+	// are merged in on every read from their own entries
+	weatherData.ExpiresAt = ws.cacheManager.Set(cacheKey, *weatherData)
+	upstreamCalls := 1 + ws.addReadings(t, client, weatherData)
 
-	go func() {
-		uv, err := ws.weatherClient.GetUVIndex(apiResponse.Coord.Lat, apiResponse.Coord.Lon)
-		if err != nil {
-			log.Printf("⚠️  Failed to get UV index: %v", err)
-			uv = -1
+	duration := time.Since(startTime)
+	ws.metricsManager.RecordRequest(duration, false, nil)
+	ws.recordTenantRequest(t, false, upstreamCalls, nil)
+	if logging.Enabled(logging.Info) {
+		log.Printf("✅ Successfully fetched and cached weather data for: %s (took %v)", weatherData.Name, duration)
+	}
+
+	return weatherData, nil
+}
+
+// addReadings fills in the UV index and air quality for the location of data.
+// The readings are cached per location under their own TTLs, so only the ones
+// that have expired are fetched, in parallel. The reading entries are peeked
+// so they do not count as weather cache hits or misses, and a failed reading
+// is cached for FailedReadingTTL so an outage is not retried on every read.
+// data.ExpiresAt is lowered to the
+// earliest expiry of the readings, so responses are not cached downstream for
+// longer than their freshest part. It returns the number of upstream calls.
+func (ws *WeatherService) addReadings(t *tenant.Tenant, client *openweathermap.Client, data *models.WeatherData) int {
+	lat, lon := data.Coordinates.Latitude, data.Coordinates.Longitude
+	uvKey := t.CacheKey("uv:" + ws.keyer.Key(lat, lon))
+	aqiKey := t.CacheKey("aqi:" + ws.keyer.Key(lat, lon))
+	upstreamCalls := 0
+
+	uvEntry, uvCached := ws.cacheManager.Peek(uvKey)
+	aqiEntry, aqiCached := ws.cacheManager.Peek(aqiKey)
+	uvData, aqiData := uvEntry.Data, aqiEntry.Data
+	uvData.ExpiresAt, aqiData.ExpiresAt = uvEntry.ExpiresAt, aqiEntry.ExpiresAt
+
+	var wg sync.WaitGroup
+	if !uvCached {
+		upstreamCalls++
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var err error
+			if uvData, err = ws.fetchUVIndex(client, uvKey, lat, lon); err != nil {
+				log.Printf("⚠️  Failed to get UV index: %v", err)
+				uvData.UVIndex = -1
+				uvData.ExpiresAt = ws.cacheManager.SetWithTTL(uvKey, uvData, min(time.Duration(ws.uvIndexTTL.Load()), FailedReadingTTL))
+			}
+		}()
+	}
+	if !aqiCached {
+		upstreamCalls++
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var err error
+			if aqiData, err = ws.fetchAirQuality(client, aqiKey, lat, lon); err != nil {
+				log.Printf("⚠️  Failed to get air quality: %v", err)
+				aqiData.AQI = -1
+				aqiData.AirQuality = "Unknown"
+				aqiData.ExpiresAt = ws.cacheManager.SetWithTTL(aqiKey, aqiData, min(time.Duration(ws.airQualityTTL.Load()), FailedReadingTTL))
+			}
+		}()
+	}
+	wg.Wait()
+
+	data.UVIndex = uvData.UVIndex
+	data.AQI = aqiData.AQI
+	data.AirQuality = aqiData.AirQuality
+	for _, part := range []models.WeatherData{uvData, aqiData} {
+		if !part.ExpiresAt.IsZero() && part.ExpiresAt.Before(data.ExpiresAt) {
+			data.ExpiresAt = part.ExpiresAt
 		}
-		uvChan <- uv
-	}()
+	}
+	return upstreamCalls
+}
+
+// fetchUVIndex fetches the UV index for a location and caches it under key
+func (ws *WeatherService) fetchUVIndex(client *openweathermap.Client, key string, lat, lon float64) (models.WeatherData, error) {
+	var data models.WeatherData
+	uv, err := client.GetUVIndex(lat, lon)
+	if err != nil {
+		return data, err
+	}
+	data.UVIndex = uv
+	data.ExpiresAt = ws.cacheManager.SetWithTTL(key, data, time.Duration(ws.uvIndexTTL.Load()))
+	return data, nil
+}
 
-	go func() {
-		aqi, quality, err := ws.weatherClient.GetAirQuality(apiResponse.Coord.Lat, apiResponse.Coord.Lon)
+// fetchAirQuality fetches air quality for a location and caches it under key
+func (ws *WeatherService) fetchAirQuality(client *openweathermap.Client, key string, lat, lon float64) (models.WeatherData, error) {
+	var data models.WeatherData
+	aqi, quality, err := client.GetAirQuality(lat, lon)
+	if err != nil {
+		return data, err
+	}
+	data.AQI = aqi
+	data.AirQuality = quality
+	data.ExpiresAt = ws.cacheManager.SetWithTTL(key, data, time.Duration(ws.airQualityTTL.Load()))
+	return data, nil
+}
+
+// RefreshCacheEntry fetches key again from upstream and replaces the cached
+// entry. If upstream fails, the cached entry is kept. key is a full cache key
+// as listed by GET /cache; keys in a tenant's namespace can only be refreshed
+// by that tenant.
+func (ws *WeatherService) RefreshCacheEntry(t *tenant.Tenant, key string) (*models.WeatherData, error) {
+	local := key
+	if namespace := t.CacheKey(""); namespace != "" {
+		if !strings.HasPrefix(key, namespace) {
+			return nil, fmt.Errorf("%w: '%s' is outside the tenant's cache namespace", ErrInvalidCacheKey, key)
+		}
+		local = strings.TrimPrefix(key, namespace)
+	}
+
+	local, opts, err := parseOptionsKey(local)
+	if err != nil {
+		return nil, fmt.Errorf("%w: '%s': %v", ErrInvalidCacheKey, key, err)
+	}
+
+	kind, rest, _ := strings.Cut(local, ":")
+	switch kind {
+	case "uv", "aqi":
+		lat, lon, ok := location.ParseKey(rest)
+		if !ok || opts != (openweathermap.Options{}) {
+			return nil, fmt.Errorf("%w: '%s'", ErrInvalidCacheKey, key)
+		}
+		if kind == "uv" {
+			data, err := ws.fetchUVIndex(ws.clientFor(t), key, lat, lon)
+			return &data, err
+		}
+		data, err := ws.fetchAirQuality(ws.clientFor(t), key, lat, lon)
+		return &data, err
+	}
+
+	if lat, lon, ok := location.ParseKey(local); ok {
+		return ws.coordsWeather(t, lat, lon, opts, true)
+	}
+	if strings.Contains(local, ":") || location.CanonicalCity(local) != local {
+		return nil, fmt.Errorf("%w: '%s'", ErrInvalidCacheKey, key)
+	}
+
+	return ws.cityWeather(t, local, opts, true)
+}
+
+// optionsSeparator separates a cache key from the options it was fetched with
+const optionsSeparator = ";"
+
+// optionsKey appends the non-default options to a cache key, as in
+// "london;units=imperial;lang=de". Metric English data keeps the plain key.
+func optionsKey(key string, opts openweathermap.Options) string {
+	if opts.Units != "" && opts.Units != openweathermap.Metric {
+		key += optionsSeparator + "units=" + string(opts.Units)
+	}
+	if opts.Lang != "" {
+		key += optionsSeparator + "lang=" + opts.Lang
+	}
+	return key
+}
+
+// parseOptionsKey splits a key built by optionsKey into the plain key and
+// its options
+func parseOptionsKey(key string) (string, openweathermap.Options, error) {
+	parts := strings.Split(key, optionsSeparator)
+	var opts openweathermap.Options
+	for _, part := range parts[1:] {
+		name, value, _ := strings.Cut(part, "=")
+		var err error
+		switch name {
+		case "units":
+			opts.Units, err = openweathermap.ParseUnits(value)
+		case "lang":
+			opts.Lang, err = openweathermap.ParseLang(value)
+		default:
+			err = fmt.Errorf("unknown option '%s'", name)
+		}
 		if err != nil {
-			log.Printf("⚠️  Failed to get air quality: %v", err)
-			aqi = -1
-			quality = "Unknown"
+			return "", opts, err
 		}
-		aqiChan <- struct {
-			aqi     int
-			quality string
-		}{aqi, quality}
-	}()
-
-	// Wait for parallel requests
-	weatherData.UVIndex = <-uvChan
-	aqData := <-aqiChan
-	weatherData.AQI = aqData.aqi
-	weatherData.AirQuality = aqData.quality
+	}
+	if optionsKey(parts[0], opts) != key {
+		return "", opts, fmt.Errorf("options are not in canonical form")
+	}
+	return parts[0], opts, nil
+}
 
-	weatherData.CacheHit = false
-	weatherData.LastUpdated = time.Now().Format("2006-01-02 15:04:05 MST")
+// clientFor returns the upstream client for a tenant, creating one for
+// tenants that bring their own OpenWeatherMap API key
+func (ws *WeatherService) clientFor(t *tenant.Tenant) *openweathermap.Client {
+	if t == nil || t.APIKey == "" {
+		return ws.weatherClient.Load()
+	}
 
-	// Cache the result
-	ws.cacheManager.Set(city, *weatherData)
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
 
-	duration := time.Since(startTime)
-	ws.metricsManager.RecordRequest(duration, false, nil)
-	log.Printf("✅ Successfully fetched and cached weather data for: %s (took %v)", weatherData.Name, duration)
+	// Keyed by API key so a reloaded tenant key gets a fresh client
+	client, exists := ws.tenantClients[t.APIKey]
+	if !exists {
+		client = openweathermap.NewClientWithBaseURL(t.APIKey, ws.weatherClient.Load().BaseURL())
+		ws.tenantClients[t.APIKey] = client
+	}
+	return client
+}
 
-	return weatherData, nil
+// recordTenantRequest records usage for chargeback; requests without a tenant
+// are attributed to the default tenant
+func (ws *WeatherService) recordTenantRequest(t *tenant.Tenant, cacheHit bool, upstreamCalls int, err error) {
+	ws.metricsManager.RecordTenantRequest(tenantID(t), cacheHit, upstreamCalls, err)
 }
 
-// transformWeatherData converts OpenWeatherMap response to our WeatherData model
-func (ws *WeatherService) transformWeatherData(apiResponse *models.OpenWeatherResponse) *models.WeatherData {
+// tenantID returns the ID usage is recorded under, DefaultID without a tenant
+func tenantID(t *tenant.Tenant) string {
+	if t == nil {
+		return tenant.DefaultID
+	}
+	return t.ID
+}
+
+// transformWeatherData converts OpenWeatherMap response to our WeatherData
+// model. units is the unit system the response was requested in.
+func (ws *WeatherService) transformWeatherData(apiResponse *models.OpenWeatherResponse, units openweathermap.Units) *models.WeatherData {
 	var data models.WeatherData
 
 	// Basic info
 	data.Name = apiResponse.Name
 	data.Country = apiResponse.Sys.Country
 	data.Timezone = apiResponse.Timezone
+	data.Dt = apiResponse.Dt
 	data.Coordinates.Latitude = apiResponse.Coord.Lat
 	data.Coordinates.Longitude = apiResponse.Coord.Lon
 
@@ -134,38 +448,30 @@ func (ws *WeatherService) transformWeatherData(apiResponse *models.OpenWeatherRe
 	data.SunsetTime = utils.UnixToTime(apiResponse.Timezone, apiResponse.Sys.Sunset)
 
 	// Temperature conversions
-	celsius := apiResponse.Main.Temp
-	kelvin, fahrenheit := utils.ConvertTemperatures(celsius)
-
-	data.Main.Celsius = celsius
-	data.Main.Kelvin = kelvin
-	data.Main.Fahrenheit = fahrenheit
+	data.Main.Kelvin, data.Main.Celsius, data.Main.Fahrenheit = temperatures(apiResponse.Main.Temp, units)
 
 	// Feels like temperature
-	feelsLikeKelvin, feelsLikeFahrenheit := utils.ConvertTemperatures(apiResponse.Main.FeelsLike)
-	data.Main.FeelsLike.Celsius = apiResponse.Main.FeelsLike
-	data.Main.FeelsLike.Kelvin = feelsLikeKelvin
-	data.Main.FeelsLike.Fahrenheit = feelsLikeFahrenheit
+	data.Main.FeelsLike.Kelvin, data.Main.FeelsLike.Celsius, data.Main.FeelsLike.Fahrenheit = temperatures(apiResponse.Main.FeelsLike, units)
 
 	// Min temperature
-	minKelvin, minFahrenheit := utils.ConvertTemperatures(apiResponse.Main.TempMin)
-	data.Main.MinTemp.Celsius = apiResponse.Main.TempMin
-	data.Main.MinTemp.Kelvin = minKelvin
-	data.Main.MinTemp.Fahrenheit = minFahrenheit
+	data.Main.MinTemp.Kelvin, data.Main.MinTemp.Celsius, data.Main.MinTemp.Fahrenheit = temperatures(apiResponse.Main.TempMin, units)
 
 	// Max temperature
-	maxKelvin, maxFahrenheit := utils.ConvertTemperatures(apiResponse.Main.TempMax)
-	data.Main.MaxTemp.Celsius = apiResponse.Main.TempMax
-	data.Main.MaxTemp.Kelvin = maxKelvin
-	data.Main.MaxTemp.Fahrenheit = maxFahrenheit
+	data.Main.MaxTemp.Kelvin, data.Main.MaxTemp.Celsius, data.Main.MaxTemp.Fahrenheit = temperatures(apiResponse.Main.TempMax, units)
 
 	// Other main data
 	data.Main.Humidity = apiResponse.Main.Humidity
 	data.Main.Pressure = apiResponse.Main.Pressure
 
-	// Wind data
-	data.Wind.Speed = apiResponse.Wind.Speed
-	data.Wind.SpeedKmh = apiResponse.Wind.Speed * 3.6 // Convert m/s to km/h
+	// Wind data; imperial responses report miles per hour
+	if units == openweathermap.Imperial {
+		data.Wind.SpeedMph = apiResponse.Wind.Speed
+		data.Wind.Speed = apiResponse.Wind.Speed * metresPerSecondPerMph
+	} else {
+		data.Wind.Speed = apiResponse.Wind.Speed
+		data.Wind.SpeedMph = apiResponse.Wind.Speed / metresPerSecondPerMph
+	}
+	data.Wind.SpeedKmh = data.Wind.Speed * 3.6 // Convert m/s to km/h
 	data.Wind.Degrees = apiResponse.Wind.Deg
 	data.Wind.Direction = utils.WindDirection(apiResponse.Wind.Deg)
 
@@ -189,4 +495,60 @@ func (ws *WeatherService) transformWeatherData(apiResponse *models.OpenWeatherRe
 	data.Visibility = apiResponse.Visibility
 
 	return &data
+}
+
+// metresPerSecondPerMph converts wind speeds between imperial and metric
+const metresPerSecondPerMph = 0.44704
+
+// temperatures returns a temperature reported in units in kelvin, Celsius and
+// Fahrenheit. The reported value is kept exact in its own scale.
+func temperatures(value float64, units openweathermap.Units) (kelvin, celsius, fahrenheit float64) {
+	switch units {
+	case openweathermap.Imperial:
+		celsius = (value - 32) * 5 / 9
+		kelvin, _ = utils.ConvertTemperatures(celsius)
+		return kelvin, celsius, value
+	case openweathermap.Standard:
+		celsius = value - 273.15
+		_, fahrenheit = utils.ConvertTemperatures(celsius)
+		return value, celsius, fahrenheit
+	}
+	kelvin, fahrenheit = utils.ConvertTemperatures(value)
+	return kelvin, value, fahrenheit
+}
+
+// SingleUnit returns data with temperatures and wind speed in units only.
+// Metric and standard wind speeds are in metres per second, imperial in
+// miles per hour.
+func SingleUnit(data *models.WeatherData, units openweathermap.Units) *models.SingleUnitWeatherData {
+	if units == "" {
+		units = openweathermap.Metric
+	}
+	view := models.SingleUnitWeatherData{WeatherData: *data, Units: string(units)}
+
+	switch units {
+	case openweathermap.Imperial:
+		view.Main.Temp = data.Main.Fahrenheit
+		view.Main.FeelsLike = data.Main.FeelsLike.Fahrenheit
+		view.Main.TempMin = data.Main.MinTemp.Fahrenheit
+		view.Main.TempMax = data.Main.MaxTemp.Fahrenheit
+		view.Wind.Speed = data.Wind.SpeedMph
+	case openweathermap.Standard:
+		view.Main.Temp = data.Main.Kelvin
+		view.Main.FeelsLike = data.Main.FeelsLike.Kelvin
+		view.Main.TempMin = data.Main.MinTemp.Kelvin
+		view.Main.TempMax = data.Main.MaxTemp.Kelvin
+		view.Wind.Speed = data.Wind.Speed
+	default:
+		view.Main.Temp = data.Main.Celsius
+		view.Main.FeelsLike = data.Main.FeelsLike.Celsius
+		view.Main.TempMin = data.Main.MinTemp.Celsius
+		view.Main.TempMax = data.Main.MaxTemp.Celsius
+		view.Wind.Speed = data.Wind.Speed
+	}
+	view.Main.Humidity = data.Main.Humidity
+	view.Main.Pressure = data.Main.Pressure
+	view.Wind.Direction = data.Wind.Direction
+	view.Wind.Degrees = data.Wind.Degrees
+	return &view
 }
\ No newline at end of file
@@ -0,0 +1,160 @@
+package tenant
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// DefaultID identifies the implicit tenant used when no tenants are configured
+const DefaultID = "default"
+
+// Tenant represents a team consuming the service
+type Tenant struct {
+	ID                 string
+	Credentials        []string
+	APIKey             string
+	RateLimitPerMinute int
+	CacheNamespace     string
+	AllowedEndpoints   []string
+}
+
+// Allows reports whether the tenant may call the given path.
+// An empty allow list permits every endpoint; entries ending in "*" match by prefix.
+// Entries without an API version prefix also match versioned paths, so
+// "/weather" allows "/v1/weather".
+func (t *Tenant) Allows(path string) bool {
+	if len(t.AllowedEndpoints) == 0 {
+		return true
+	}
+	unversioned := stripVersion(path)
+	for _, endpoint := range t.AllowedEndpoints {
+		if strings.HasSuffix(endpoint, "*") {
+			prefix := strings.TrimSuffix(endpoint, "*")
+			if strings.HasPrefix(path, prefix) || strings.HasPrefix(unversioned, prefix) {
+				return true
+			}
+		} else if path == endpoint || unversioned == endpoint {
+			return true
+		}
+	}
+	return false
+}
+
+// stripVersion removes a leading API version segment such as "/v1"
+func stripVersion(path string) string {
+	rest := strings.TrimPrefix(path, "/v")
+	if rest == path {
+		return path
+	}
+	i := strings.IndexByte(rest, '/')
+	if i <= 0 {
+		return path
+	}
+	for _, c := range rest[:i] {
+		if c < '0' || c > '9' {
+			return path
+		}
+	}
+	return rest[i:]
+}
+
+// CacheKey scopes a cache key to the tenant's namespace
+func (t *Tenant) CacheKey(key string) string {
+	if t == nil || t.CacheNamespace == "" {
+		return key
+	}
+	return t.CacheNamespace + ":" + key
+}
+
+// Registry resolves tenants from caller credentials
+type Registry struct {
+	state atomic.Pointer[registryState]
+}
+
+// registryState is an immutable set of tenants, swapped as a whole on reload
+type registryState struct {
+	tenants  []*Tenant
+	byID     map[string]*Tenant
+	fallback *Tenant
+}
+
+// NewRegistry creates a registry for the given tenants. When tenants is
+// empty every caller resolves to fallback.
+func NewRegistry(tenants []*Tenant, fallback *Tenant) *Registry {
+	r := &Registry{}
+	r.Replace(tenants, fallback)
+	return r
+}
+
+// Replace atomically swaps in a new set of tenants. Requests already
+// resolved keep the tenant they were given.
+func (r *Registry) Replace(tenants []*Tenant, fallback *Tenant) {
+	state := &registryState{
+		tenants:  tenants,
+		byID:     make(map[string]*Tenant, len(tenants)),
+		fallback: fallback,
+	}
+	for _, t := range tenants {
+		state.byID[t.ID] = t
+	}
+	r.state.Store(state)
+}
+
+// Enabled reports whether callers must identify a configured tenant
+func (r *Registry) Enabled() bool {
+	return len(r.state.Load().tenants) > 0
+}
+
+// Get returns the tenant with the given ID
+func (r *Registry) Get(id string) (*Tenant, bool) {
+	t, found := r.state.Load().byID[id]
+	return t, found
+}
+
+// Tenants returns all configured tenants
+func (r *Registry) Tenants() []*Tenant {
+	return r.state.Load().tenants
+}
+
+// Resolve identifies the tenant for a request. A tenant ID asserted by a
+// verified token takes precedence over the X-API-Key header.
+func (r *Registry) Resolve(req *http.Request, tokenTenant string) (*Tenant, bool) {
+	state := r.state.Load()
+	if len(state.tenants) == 0 {
+		return state.fallback, true
+	}
+
+	if tokenTenant != "" {
+		t, found := state.byID[tokenTenant]
+		return t, found
+	}
+
+	credential := req.Header.Get("X-API-Key")
+	if credential == "" {
+		return nil, false
+	}
+	for _, t := range state.tenants {
+		for _, c := range t.Credentials {
+			if subtle.ConstantTimeCompare([]byte(c), []byte(credential)) == 1 {
+				return t, true
+			}
+		}
+	}
+	return nil, false
+}
+
+type contextKey struct{}
+
+// WithTenant returns a copy of ctx carrying the tenant
+func WithTenant(ctx context.Context, t *Tenant) context.Context {
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+// FromContext returns the tenant stored in ctx, if any
+func FromContext(ctx context.Context) (*Tenant, bool) {
+	t, ok := ctx.Value(contextKey{}).(*Tenant)
+	return t, ok
+}
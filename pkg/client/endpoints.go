@@ -0,0 +1,193 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/models"
+)
+
+// apiPrefix is the API version the client speaks
+const apiPrefix = "/v1"
+
+// WeatherData is the weather returned by the service
+type WeatherData = models.WeatherData
+
+// HealthStatus is returned by Health
+type HealthStatus struct {
+	Status       string `json:"status"`
+	Timestamp    string `json:"timestamp"`
+	CacheEntries int    `json:"cache_entries"`
+	Service      string `json:"service"`
+	Version      string `json:"version"`
+}
+
+// ReadinessStatus is returned by Readiness
+type ReadinessStatus struct {
+	Status    string          `json:"status"`
+	Timestamp string          `json:"timestamp"`
+	Checks    map[string]bool `json:"checks"`
+}
+
+// CacheEntry is a single cache entry returned by CacheEntry. Negative entries
+// record a city that upstream does not know and carry no data.
+type CacheEntry struct {
+	Key              string       `json:"key"`
+	Negative         bool         `json:"negative"`
+	ExpiresAt        time.Time    `json:"expires_at"`
+	ExpiresInSeconds int          `json:"expires_in_seconds"`
+	StoredAt         time.Time    `json:"stored_at"`
+	AgeSeconds       int          `json:"age_seconds"`
+	Data             *WeatherData `json:"data"`
+}
+
+// WeatherOption customizes a weather lookup
+type WeatherOption func(query url.Values)
+
+// InUnits asks the service to fetch the weather in units: metric, imperial
+// or standard. Temperatures are still returned in every scale.
+func InUnits(units string) WeatherOption {
+	return func(query url.Values) { query.Set("units", units) }
+}
+
+// InLanguage asks for condition descriptions in lang, such as "de"
+func InLanguage(lang string) WeatherOption {
+	return func(query url.Values) { query.Set("lang", lang) }
+}
+
+// Weather returns the current weather for a city
+func (c *Client) Weather(ctx context.Context, city string, opts ...WeatherOption) (*WeatherData, error) {
+	query := url.Values{"city": {city}}
+	for _, opt := range opts {
+		opt(query)
+	}
+	var data WeatherData
+	if err := c.do(ctx, http.MethodGet, apiPrefix+"/weather", query, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// WeatherByCoords returns the current weather at a latitude and longitude
+func (c *Client) WeatherByCoords(ctx context.Context, lat, lon float64, opts ...WeatherOption) (*WeatherData, error) {
+	query := url.Values{
+		"lat": {strconv.FormatFloat(lat, 'f', -1, 64)},
+		"lon": {strconv.FormatFloat(lon, 'f', -1, 64)},
+	}
+	for _, opt := range opts {
+		opt(query)
+	}
+	var data WeatherData
+	if err := c.do(ctx, http.MethodGet, apiPrefix+"/weather", query, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// Health returns the service health
+func (c *Client) Health(ctx context.Context) (*HealthStatus, error) {
+	var status HealthStatus
+	if err := c.do(ctx, http.MethodGet, "/health", nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Readiness returns whether the service is ready to accept traffic
+func (c *Client) Readiness(ctx context.Context) (*ReadinessStatus, error) {
+	var status ReadinessStatus
+	if err := c.do(ctx, http.MethodGet, "/readiness", nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Metrics returns the service performance metrics
+func (c *Client) Metrics(ctx context.Context) (map[string]interface{}, error) {
+	var metrics map[string]interface{}
+	if err := c.do(ctx, http.MethodGet, "/metrics", nil, &metrics); err != nil {
+		return nil, err
+	}
+	return metrics, nil
+}
+
+// Config returns the active configuration with secrets redacted. Requires
+// the admin scope.
+func (c *Client) Config(ctx context.Context) (map[string]interface{}, error) {
+	var cfg map[string]interface{}
+	if err := c.do(ctx, http.MethodGet, apiPrefix+"/config", nil, &cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// CacheStats returns cache hit rates and entries. Requires the admin scope.
+func (c *Client) CacheStats(ctx context.Context) (map[string]interface{}, error) {
+	var stats map[string]interface{}
+	if err := c.do(ctx, http.MethodGet, apiPrefix+"/cache", nil, &stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// ClearCache removes every cache entry. Requires the admin scope.
+func (c *Client) ClearCache(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, apiPrefix+"/cache/clear", nil, nil)
+}
+
+// ClearNegativeCache removes only cached "not found" results. Requires the
+// admin scope.
+func (c *Client) ClearNegativeCache(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, apiPrefix+"/cache/clear", url.Values{"type": {"negative"}}, nil)
+}
+
+// CacheEntry returns a single cache entry. Requires the admin scope.
+func (c *Client) CacheEntry(ctx context.Context, key string) (*CacheEntry, error) {
+	var entry CacheEntry
+	if err := c.do(ctx, http.MethodGet, apiPrefix+"/cache/"+key, nil, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// DeleteCacheEntry removes a single cache entry. Requires the admin scope.
+func (c *Client) DeleteCacheEntry(ctx context.Context, key string) error {
+	return c.do(ctx, http.MethodDelete, apiPrefix+"/cache/"+key, nil, nil)
+}
+
+// RefreshCacheEntry fetches a cache entry again from upstream and returns the
+// fresh data. Requires the admin scope.
+func (c *Client) RefreshCacheEntry(ctx context.Context, key string) (*WeatherData, error) {
+	var response struct {
+		Data WeatherData `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodPost, apiPrefix+"/cache/"+key+"/refresh", nil, &response); err != nil {
+		return nil, err
+	}
+	return &response.Data, nil
+}
+
+// InvalidatePrefix removes every cache entry whose key starts with prefix and
+// returns how many were removed. Requires the admin scope.
+func (c *Client) InvalidatePrefix(ctx context.Context, prefix string) (int, error) {
+	return c.invalidate(ctx, url.Values{"prefix": {prefix}})
+}
+
+// InvalidatePattern removes every cache entry matching a glob such as "acme:*"
+// and returns how many were removed. Requires the admin scope.
+func (c *Client) InvalidatePattern(ctx context.Context, pattern string) (int, error) {
+	return c.invalidate(ctx, url.Values{"pattern": {pattern}})
+}
+
+func (c *Client) invalidate(ctx context.Context, query url.Values) (int, error) {
+	var response struct {
+		Deleted int `json:"deleted"`
+	}
+	if err := c.do(ctx, http.MethodDelete, apiPrefix+"/cache", query, &response); err != nil {
+		return 0, err
+	}
+	return response.Deleted, nil
+}
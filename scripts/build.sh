@@ -44,7 +44,7 @@ go test -v ./... || {
 
 # Build for current platform
 echo -e "${YELLOW}🔨 Building for current platform...${NC}"
-go build -o bin/weather-server -ldflags="-s -w" ./cmd/server/main.go
+go build -o bin/weather-server -ldflags="-s -w" ./cmd/server
 
 echo -e "${GREEN}✅ Build successful!${NC}"
 echo -e "${GREEN}📦 Binary: bin/weather-server${NC}"
@@ -53,19 +53,19 @@ echo -e "${GREEN}📦 Binary: bin/weather-server${NC}"
 echo -e "${YELLOW}🔨 Building for multiple platforms...${NC}"
 
 # Linux
-GOOS=linux GOARCH=amd64 go build -o bin/weather-server-linux-amd64 -ldflags="-s -w" ./cmd/server/main.go
+GOOS=linux GOARCH=amd64 go build -o bin/weather-server-linux-amd64 -ldflags="-s -w" ./cmd/server
 echo -e "${GREEN}✅ Built: bin/weather-server-linux-amd64${NC}"
 
 # macOS
-GOOS=darwin GOARCH=amd64 go build -o bin/weather-server-darwin-amd64 -ldflags="-s -w" ./cmd/server/main.go
+GOOS=darwin GOARCH=amd64 go build -o bin/weather-server-darwin-amd64 -ldflags="-s -w" ./cmd/server
 echo -e "${GREEN}✅ Built: bin/weather-server-darwin-amd64${NC}"
 
 # Windows
-GOOS=windows GOARCH=amd64 go build -o bin/weather-server-windows-amd64.exe -ldflags="-s -w" ./cmd/server/main.go
+GOOS=windows GOARCH=amd64 go build -o bin/weather-server-windows-amd64.exe -ldflags="-s -w" ./cmd/server
 echo -e "${GREEN}✅ Built: bin/weather-server-windows-amd64.exe${NC}"
 
 # ARM64 for Raspberry Pi / ARM servers
-GOOS=linux GOARCH=arm64 go build -o bin/weather-server-linux-arm64 -ldflags="-s -w" ./cmd/server/main.go
+GOOS=linux GOARCH=arm64 go build -o bin/weather-server-linux-arm64 -ldflags="-s -w" ./cmd/server
 echo -e "${GREEN}✅ Built: bin/weather-server-linux-arm64${NC}"
 
 echo ""
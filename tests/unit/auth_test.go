@@ -0,0 +1,252 @@
+package unit
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/auth"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/middleware"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testIssuer   = "https://idp.example.com"
+	testAudience = "weather-api"
+	testKid      = "test-key"
+)
+
+// newJWKSServer serves the public half of key as a JWKS document
+func newJWKSServer(t *testing.T, key *rsa.PrivateKey) *httptest.Server {
+	t.Helper()
+	jwks := map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": testKid,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(jwks)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func signToken(t *testing.T, key *rsa.PrivateKey, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = testKid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return signed
+}
+
+func validClaims(scope string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":   testIssuer,
+		"aud":   testAudience,
+		"sub":   "service-a",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": scope,
+	}
+}
+
+func TestAuthMiddleware_JWKS(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	jwksServer := newJWKSServer(t, key)
+
+	verifier, err := auth.NewVerifier(auth.Options{
+		JWKSURL:  jwksServer.URL,
+		Issuer:   testIssuer,
+		Audience: testAudience,
+	})
+	if err != nil {
+		t.Fatalf("NewVerifier() error = %v", err)
+	}
+
+	handler := middleware.AuthMiddleware(verifier, auth.ScopeWeatherRead)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := auth.ClaimsFromContext(r.Context()); !ok {
+				t.Error("Expected claims in request context")
+			}
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+
+	expired := validClaims(auth.ScopeWeatherRead)
+	expired["exp"] = time.Now().Add(-time.Hour).Unix()
+	wrongIssuer := validClaims(auth.ScopeWeatherRead)
+	wrongIssuer["iss"] = "https://evil.example.com"
+	wrongAudience := validClaims(auth.ScopeWeatherRead)
+	wrongAudience["aud"] = "other-api"
+	scpArray := validClaims("")
+	delete(scpArray, "scope")
+	scpArray["scp"] = []string{"profile", auth.ScopeWeatherRead}
+
+	tests := []struct {
+		name       string
+		header     string
+		wantStatus int
+	}{
+		{"Missing token", "", http.StatusUnauthorized},
+		{"Malformed header", "Basic abc", http.StatusUnauthorized},
+		{"Valid token", "Bearer " + signToken(t, key, validClaims(auth.ScopeWeatherRead)), http.StatusOK},
+		{"Admin scope implies read", "Bearer " + signToken(t, key, validClaims(auth.ScopeAdmin)), http.StatusOK},
+		{"Scope from scp array", "Bearer " + signToken(t, key, scpArray), http.StatusOK},
+		{"Missing scope", "Bearer " + signToken(t, key, validClaims("profile")), http.StatusForbidden},
+		{"Expired token", "Bearer " + signToken(t, key, expired), http.StatusUnauthorized},
+		{"Wrong issuer", "Bearer " + signToken(t, key, wrongIssuer), http.StatusUnauthorized},
+		{"Wrong audience", "Bearer " + signToken(t, key, wrongAudience), http.StatusUnauthorized},
+		{"Unknown signing key", "Bearer " + signToken(t, otherKey, validClaims(auth.ScopeWeatherRead)), http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/weather?city=London", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d (%s)", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestAuthMiddleware_JWKSSingleFetch(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	jwksServer := newJWKSServer(t, key)
+	var fetches atomic.Int64
+	release := make(chan struct{})
+	gated := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		<-release
+		jwksServer.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(gated.Close)
+
+	verifier, err := auth.NewVerifier(auth.Options{JWKSURL: gated.URL, Issuer: testIssuer, Audience: testAudience})
+	if err != nil {
+		t.Fatalf("NewVerifier() error = %v", err)
+	}
+	handler := middleware.AuthMiddleware(verifier, auth.ScopeWeatherRead)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }),
+	)
+	header := "Bearer " + signToken(t, key, validClaims(auth.ScopeWeatherRead))
+
+	const requests = 10
+	codes := make(chan int, requests)
+	for i := 0; i < requests; i++ {
+		go func() {
+			req := httptest.NewRequest(http.MethodGet, "/weather?city=London", nil)
+			req.Header.Set("Authorization", header)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			codes <- rec.Code
+		}()
+	}
+
+	// Every request is now waiting on the first fetch
+	waitFor(t, func() bool { return fetches.Load() > 0 })
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	for i := 0; i < requests; i++ {
+		if code := <-codes; code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", code)
+		}
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("Expected concurrent requests to share 1 JWKS fetch, got %d", n)
+	}
+}
+
+func TestAuthMiddleware_KeyFile(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("failed to marshal public key: %v", err)
+	}
+	keyFile := filepath.Join(t.TempDir(), "jwt.pem")
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600); err != nil {
+		t.Fatalf("failed to write key file: %v", err)
+	}
+
+	verifier, err := auth.NewVerifier(auth.Options{KeyFile: keyFile, Issuer: testIssuer, Audience: testAudience})
+	if err != nil {
+		t.Fatalf("NewVerifier() error = %v", err)
+	}
+
+	claims, err := verifier.Verify(signToken(t, key, validClaims(auth.ScopeAdmin)))
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if !claims.HasScope(auth.ScopeAdmin) {
+		t.Errorf("Expected admin scope, got %v", claims.Scopes())
+	}
+
+	wrongAudience := validClaims(auth.ScopeAdmin)
+	wrongAudience["aud"] = "other-api"
+	if _, err := verifier.Verify(signToken(t, key, wrongAudience)); err == nil {
+		t.Error("Expected a token for another audience to be rejected")
+	}
+
+	// Without an issuer and audience any token from the same identity
+	// provider would be accepted
+	for _, opts := range []auth.Options{
+		{KeyFile: keyFile, Audience: testAudience},
+		{KeyFile: keyFile, Issuer: testIssuer},
+	} {
+		if _, err := auth.NewVerifier(opts); err == nil {
+			t.Errorf("Expected NewVerifier(%+v) to fail", opts)
+		}
+	}
+}
+
+func TestAuthMiddleware_Disabled(t *testing.T) {
+	handler := middleware.AuthMiddleware(nil, auth.ScopeAdmin)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/cache/clear", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status 200 with auth disabled, got %d", rec.Code)
+	}
+}
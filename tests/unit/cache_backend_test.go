@@ -0,0 +1,245 @@
+package unit
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/cache"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/models"
+	"github.com/alicebob/miniredis/v2"
+)
+
+// backendFactories returns every backend the contract tests run against.
+// Set REDIS_ADDR to also run them against a locally started redis-server.
+func backendFactories(t *testing.T) map[string]func(t *testing.T) cache.Backend {
+	factories := map[string]func(t *testing.T) cache.Backend{
+		"memory": func(t *testing.T) cache.Backend {
+			return cache.NewMemoryBackend()
+		},
+		"miniredis": func(t *testing.T) cache.Backend {
+			server := miniredis.RunT(t)
+			backend, err := cache.NewRedisBackend(cache.RedisOptions{Addr: server.Addr()})
+			if err != nil {
+				t.Fatalf("NewRedisBackend() error = %v", err)
+			}
+			return backend
+		},
+	}
+
+	if addr := os.Getenv("REDIS_ADDR"); addr != "" {
+		factories["redis"] = func(t *testing.T) cache.Backend {
+			backend, err := cache.NewRedisBackend(cache.RedisOptions{
+				Addr:      addr,
+				KeyPrefix: "weather-test:" + t.Name() + ":",
+			})
+			if err != nil {
+				t.Fatalf("NewRedisBackend() error = %v", err)
+			}
+			t.Cleanup(func() { backend.Clear() })
+			return backend
+		}
+	}
+	return factories
+}
+
+func TestCacheBackends(t *testing.T) {
+	for name, newBackend := range backendFactories(t) {
+		t.Run(name, func(t *testing.T) {
+			backend := newBackend(t)
+			defer backend.Close()
+
+			data := models.WeatherData{Name: "London", Country: "GB", UVIndex: 3.5}
+			data.Main.Celsius = 18.2
+
+			if _, found, err := backend.Get("london"); found || err != nil {
+				t.Fatalf("Expected miss on empty backend, got found=%v err=%v", found, err)
+			}
+
+			if err := backend.Set("london", data, time.Minute); err != nil {
+				t.Fatalf("Set() error = %v", err)
+			}
+
+			entry, found, err := backend.Get("london")
+			if err != nil || !found {
+				t.Fatalf("Expected hit, got found=%v err=%v", found, err)
+			}
+			if entry.Data.Name != "London" || entry.Data.Main.Celsius != 18.2 || entry.Data.UVIndex != 3.5 {
+				t.Errorf("Round-tripped data mismatch: %+v", entry.Data)
+			}
+			if remaining := time.Until(entry.ExpiresAt); remaining <= 0 || remaining > time.Minute {
+				t.Errorf("Expected expiry within a minute, got %v", remaining)
+			}
+			if age := time.Since(entry.StoredAt); age < 0 || age > time.Minute {
+				t.Errorf("Expected write time to be recorded, got age %v", age)
+			}
+
+			backend.Set("paris", data, time.Minute)
+			entries, err := backend.Entries()
+			if err != nil || len(entries) != 2 {
+				t.Errorf("Expected 2 entries, got %v (err=%v)", entries, err)
+			}
+
+			if err := backend.Delete("london"); err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+			if _, found, _ := backend.Get("london"); found {
+				t.Error("Expected deleted key to be gone")
+			}
+
+			if err := backend.Clear(); err != nil {
+				t.Fatalf("Clear() error = %v", err)
+			}
+			if entries, _ := backend.Entries(); len(entries) != 0 {
+				t.Errorf("Expected no entries after Clear, got %v", entries)
+			}
+		})
+	}
+}
+
+func TestRedisBackend_Expiry(t *testing.T) {
+	server := miniredis.RunT(t)
+	backend, err := cache.NewRedisBackend(cache.RedisOptions{Addr: server.Addr()})
+	if err != nil {
+		t.Fatalf("NewRedisBackend() error = %v", err)
+	}
+	defer backend.Close()
+
+	backend.Set("london", models.WeatherData{Name: "London"}, 10*time.Second)
+	server.FastForward(11 * time.Second)
+
+	if _, found, _ := backend.Get("london"); found {
+		t.Error("Expected entry to expire with its TTL")
+	}
+}
+
+func TestRedisBackend_ClearOnlyOwnPrefix(t *testing.T) {
+	server := miniredis.RunT(t)
+	server.Set("unrelated", "keep me")
+
+	backend, err := cache.NewRedisBackend(cache.RedisOptions{Addr: server.Addr()})
+	if err != nil {
+		t.Fatalf("NewRedisBackend() error = %v", err)
+	}
+	defer backend.Close()
+
+	backend.Set("london", models.WeatherData{Name: "London"}, time.Minute)
+	backend.Clear()
+
+	if !server.Exists("unrelated") {
+		t.Error("Expected Clear to leave keys outside the prefix alone")
+	}
+}
+
+func TestRedisBackend_CountAndList(t *testing.T) {
+	server := miniredis.RunT(t)
+	backend, err := cache.NewRedisBackend(cache.RedisOptions{Addr: server.Addr()})
+	if err != nil {
+		t.Fatalf("NewRedisBackend() error = %v", err)
+	}
+	defer backend.Close()
+
+	for i := 0; i < 60; i++ {
+		backend.Set(fmt.Sprintf("acme:city-%d", i), models.WeatherData{}, time.Minute)
+	}
+	for i := 0; i < 40; i++ {
+		backend.Set(fmt.Sprintf("globex:city-%d", i), models.WeatherData{}, time.Minute)
+	}
+	backend.Set("a*b:city", models.WeatherData{}, time.Minute)
+
+	// Counting must not look up the TTL of every key
+	before := server.CommandCount()
+	count, err := backend.Count("acme:")
+	if err != nil || count != 60 {
+		t.Errorf("Expected 60 acme keys, got %d (err=%v)", count, err)
+	}
+	if commands := server.CommandCount() - before; commands > 5 {
+		t.Errorf("Expected counting to only scan, got %d commands", commands)
+	}
+	if count, _ := backend.Count("a*"); count != 1 {
+		t.Errorf("Expected glob characters in the prefix to match literally, got %d keys", count)
+	}
+
+	entries, truncated, err := backend.EntriesWithPrefix("", 10)
+	if err != nil || len(entries) != 10 || !truncated {
+		t.Errorf("Expected 10 of 101 keys, got %d (truncated=%v, err=%v)", len(entries), truncated, err)
+	}
+	entries, truncated, _ = backend.EntriesWithPrefix("globex:", 100)
+	if len(entries) != 40 || truncated {
+		t.Errorf("Expected all 40 globex keys, got %d (truncated=%v)", len(entries), truncated)
+	}
+
+	// The total behind /health is reused instead of scanning on every probe
+	if count, _ := backend.Count(""); count != 101 {
+		t.Errorf("Expected 101 keys, got %d", count)
+	}
+	before = server.CommandCount()
+	backend.Count("")
+	if commands := server.CommandCount() - before; commands != 0 {
+		t.Errorf("Expected the total to be reused, got %d commands", commands)
+	}
+	backend.Clear()
+	if count, _ := backend.Count(""); count != 0 {
+		t.Errorf("Expected no keys after Clear, got %d", count)
+	}
+}
+
+func TestRedisBackend_ClearInBatches(t *testing.T) {
+	server := miniredis.RunT(t)
+	server.Set("other:key", "kept")
+	backend, err := cache.NewRedisBackend(cache.RedisOptions{Addr: server.Addr()})
+	if err != nil {
+		t.Fatalf("NewRedisBackend() error = %v", err)
+	}
+	defer backend.Close()
+
+	for i := 0; i < 1200; i++ {
+		backend.Set(fmt.Sprintf("city-%d", i), models.WeatherData{}, time.Minute)
+	}
+	if entries, err := backend.Entries(); err != nil || len(entries) != 1200 {
+		t.Errorf("Expected 1200 entries, got %d (err=%v)", len(entries), err)
+	}
+	if err := backend.Clear(); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if keys := server.Keys(); len(keys) != 1 || keys[0] != "other:key" {
+		t.Errorf("Expected only the unrelated key to remain, got %d keys", len(keys))
+	}
+}
+
+func TestCacheManager_StatsListingCapped(t *testing.T) {
+	cm := cache.NewCacheManager(10 * time.Minute)
+	for i := 0; i < cache.MaxListedEntries+5; i++ {
+		cm.Set(fmt.Sprintf("city-%d", i), models.WeatherData{})
+	}
+
+	stats := cm.GetStats()
+	entries := stats["entries"].(map[string]string)
+	if len(entries) != cache.MaxListedEntries || stats["entries_truncated"] != true {
+		t.Errorf("Expected %d listed entries, got %d (truncated=%v)", cache.MaxListedEntries, len(entries), stats["entries_truncated"])
+	}
+	if stats["total_entries"] != cache.MaxListedEntries+5 || cm.GetSize() != cache.MaxListedEntries+5 {
+		t.Errorf("Expected every entry to be counted, got %v and %d", stats["total_entries"], cm.GetSize())
+	}
+}
+
+func TestCacheManager_SharedBackend(t *testing.T) {
+	server := miniredis.RunT(t)
+	newReplica := func() *cache.CacheManager {
+		backend, err := cache.NewRedisBackend(cache.RedisOptions{Addr: server.Addr()})
+		if err != nil {
+			t.Fatalf("NewRedisBackend() error = %v", err)
+		}
+		t.Cleanup(func() { backend.Close() })
+		return cache.NewCacheManagerWithBackend(10*time.Minute, backend)
+	}
+
+	replicaA, replicaB := newReplica(), newReplica()
+	replicaA.Set("london", models.WeatherData{Name: "London"})
+
+	data, found := replicaB.Get("london")
+	if !found || data.Name != "London" {
+		t.Error("Expected replica B to see data cached by replica A")
+	}
+}
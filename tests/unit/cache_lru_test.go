@@ -0,0 +1,89 @@
+package unit
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/cache"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/models"
+)
+
+func TestMemoryBackend_LRUEviction(t *testing.T) {
+	backend := cache.NewBoundedMemoryBackend(3, 0)
+	defer backend.Close()
+
+	for _, city := range []string{"london", "paris", "berlin"} {
+		backend.Set(city, models.WeatherData{Name: city}, time.Minute)
+	}
+
+	// Touch london so paris becomes the least recently used
+	backend.Get("london")
+	backend.Set("madrid", models.WeatherData{Name: "madrid"}, time.Minute)
+
+	if _, found, _ := backend.Get("paris"); found {
+		t.Error("Expected least recently used entry to be evicted")
+	}
+	for _, city := range []string{"london", "berlin", "madrid"} {
+		if _, found, _ := backend.Get(city); !found {
+			t.Errorf("Expected %s to remain cached", city)
+		}
+	}
+	if evictions := backend.Evictions(); evictions != 1 {
+		t.Errorf("Expected 1 eviction, got %d", evictions)
+	}
+}
+
+func TestMemoryBackend_MaxBytes(t *testing.T) {
+	backend := cache.NewBoundedMemoryBackend(0, 4096)
+	defer backend.Close()
+
+	large := models.WeatherData{Name: strings.Repeat("x", 1500)}
+	for i := 0; i < 10; i++ {
+		backend.Set(fmt.Sprintf("city-%d", i), large, time.Minute)
+	}
+
+	stats := backend.Stats()
+	if stats["bytes"].(int64) > 4096 {
+		t.Errorf("Expected byte usage within limit, got %v", stats["bytes"])
+	}
+	if backend.Evictions() == 0 {
+		t.Error("Expected evictions once the byte limit was reached")
+	}
+	if _, found, _ := backend.Get("city-9"); !found {
+		t.Error("Expected the newest entry to be kept")
+	}
+}
+
+func TestMemoryBackend_OverwriteDoesNotEvict(t *testing.T) {
+	backend := cache.NewBoundedMemoryBackend(2, 0)
+	defer backend.Close()
+
+	backend.Set("london", models.WeatherData{Name: "London"}, time.Minute)
+	backend.Set("paris", models.WeatherData{Name: "Paris"}, time.Minute)
+	backend.Set("london", models.WeatherData{Name: "London"}, time.Minute)
+
+	if evictions := backend.Evictions(); evictions != 0 {
+		t.Errorf("Expected no evictions when overwriting, got %d", evictions)
+	}
+}
+
+func TestCacheManager_EvictionStats(t *testing.T) {
+	cm := cache.NewCacheManagerWithBackend(10*time.Minute, cache.NewBoundedMemoryBackend(1, 0))
+	cm.Set("london", models.WeatherData{Name: "London"})
+	cm.Set("paris", models.WeatherData{Name: "Paris"})
+
+	stats := cm.GetStats()
+	backendStats := stats["backend"].(map[string]interface{})
+	if backendStats["evictions"].(int64) != 1 || cm.Evictions() != 1 {
+		t.Errorf("Expected 1 eviction in stats, got %v", backendStats["evictions"])
+	}
+	if stats["total_entries"].(int) != 1 {
+		t.Errorf("Expected 1 entry, got %v", stats["total_entries"])
+	}
+	entries, ok := stats["entries"].(map[string]string)
+	if !ok || len(entries) != 1 || entries["paris"] == "" {
+		t.Errorf("Expected entries to map keys to expiry times, got %v", stats["entries"])
+	}
+}
@@ -0,0 +1,138 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/api/openweathermap"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/cache"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/config"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/handlers"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/metrics"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/models"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/services"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/tenant"
+)
+
+func TestCacheManager_NegativeEntries(t *testing.T) {
+	cm := cache.NewCacheManager(10 * time.Minute)
+	cm.SetNegativeCacheTime(50 * time.Millisecond)
+	cm.SetNotFound("lndon")
+	cm.Set("london", models.WeatherData{Name: "London"})
+
+	if !cm.IsNotFound("lndon") {
+		t.Fatal("Expected lndon to be negatively cached")
+	}
+	if cm.IsNotFound("london") {
+		t.Error("Expected london not to be negatively cached")
+	}
+
+	stats := cm.GetStats()
+	if stats["negative_entries"].(int) != 1 || stats["total_entries"].(int) != 1 {
+		t.Errorf("Expected negative entries reported separately, got %v", stats)
+	}
+	if stats["negative_hit_count"].(int64) != 1 {
+		t.Errorf("Expected 1 negative hit, got %v", stats["negative_hit_count"])
+	}
+
+	time.Sleep(80 * time.Millisecond)
+	if cm.IsNotFound("lndon") {
+		t.Error("Expected negative entry to expire on its own TTL")
+	}
+}
+
+func TestCacheManager_ClearNegative(t *testing.T) {
+	cm := cache.NewCacheManager(10 * time.Minute)
+	cm.SetNotFound("lndon")
+	cm.Set("london", models.WeatherData{Name: "London"})
+
+	cm.ClearNegative()
+	if cm.IsNotFound("lndon") {
+		t.Error("Expected negative entry to be cleared")
+	}
+	if _, found := cm.Get("london"); !found {
+		t.Error("Expected weather entries to survive ClearNegative")
+	}
+
+	cm.SetNotFound("lndon")
+	cm.Clear()
+	if cm.IsNotFound("lndon") {
+		t.Error("Expected Clear to drop negative entries too")
+	}
+}
+
+func TestWeatherHandler_NegativeCacheReturns404(t *testing.T) {
+	cacheManager := cache.NewCacheManager(10 * time.Minute)
+	metricsManager := metrics.NewMetricsManager()
+	service := services.NewWeatherService(openweathermap.NewClient("test_api_key"), cacheManager, metricsManager)
+	handler := handlers.NewHandler(service, metricsManager, cacheManager)
+
+	// A negative hit must be answered without going upstream
+	cacheManager.SetNotFound("lndon")
+	rec := httptest.NewRecorder()
+	handler.WeatherHandler(rec, httptest.NewRequest(http.MethodGet, "/weather?city=Lndon", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", rec.Code)
+	}
+	if hits := cacheManager.GetStats()["negative_hit_count"].(int64); hits != 1 {
+		t.Errorf("Expected 1 negative hit, got %d", hits)
+	}
+
+	// A negative hit is neither a cache hit nor an error
+	stats := metricsManager.GetMetrics()
+	if stats["negative_hits"].(int64) != 1 || stats["cache_hits"].(int64) != 0 || stats["errors"].(int64) != 0 {
+		t.Errorf("Expected the request to count as a negative hit only, got %v", stats)
+	}
+	if usage := metricsManager.GetTenantUsage()[tenant.DefaultID]; usage.NegativeHits != 1 || usage.CacheHits != 0 || usage.Errors != 0 {
+		t.Errorf("Expected tenant usage to count a negative hit only, got %+v", usage)
+	}
+}
+
+func TestWeatherService_NegativeCacheOff(t *testing.T) {
+	upstream := newFakeUpstream(t)
+	cacheManager := cache.NewCacheManager(10 * time.Minute)
+	service := services.NewWeatherService(openweathermap.NewClientWithBaseURL("test_api_key", upstream.URL), cacheManager, metrics.NewMetricsManager())
+
+	cacheManager.SetNotFound("paris")
+	cacheManager.SetNegativeCacheTime(time.Duration(config.NegativeCacheOff) * time.Minute)
+	if cacheManager.IsNotFound("paris") {
+		t.Error("Expected existing negative entries to be dropped")
+	}
+
+	service.GetWeatherData("lndon")
+	service.GetWeatherData("lndon")
+	if calls := upstream.Calls("weather"); calls != 2 {
+		t.Errorf("Expected every lookup to go upstream, got %d calls", calls)
+	}
+	if stats := cacheManager.GetStats(); stats["negative_entries"].(int) != 0 || stats["negative_cache_duration"] != "0s" {
+		t.Errorf("Expected negative caching to be off, got %v", stats)
+	}
+}
+
+func TestCacheClearHandler_Negative(t *testing.T) {
+	cacheManager := cache.NewCacheManager(10 * time.Minute)
+	metricsManager := metrics.NewMetricsManager()
+	service := services.NewWeatherService(openweathermap.NewClient("test_api_key"), cacheManager, metricsManager)
+	handler := handlers.NewHandler(service, metricsManager, cacheManager)
+
+	cacheManager.SetNotFound("lndon")
+	cacheManager.Set("london", models.WeatherData{Name: "London"})
+
+	rec := httptest.NewRecorder()
+	handler.CacheClearHandler(rec, httptest.NewRequest(http.MethodPost, "/cache/clear?type=negative", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	if cacheManager.IsNotFound("lndon") || cacheManager.GetSize() != 1 {
+		t.Error("Expected only negative entries to be cleared")
+	}
+
+	rec = httptest.NewRecorder()
+	handler.CacheClearHandler(rec, httptest.NewRequest(http.MethodPost, "/cache/clear?type=bogus", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for unknown type, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,144 @@
+package unit
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/api/openweathermap"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/cache"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/handlers"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/location"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/metrics"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/models"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/services"
+)
+
+const benchCities = 256
+
+func TestShardedMemoryBackend_Concurrent(t *testing.T) {
+	backend := cache.NewShardedMemoryBackend(8, 0, 0)
+	defer backend.Close()
+
+	var wg sync.WaitGroup
+	for w := 0; w < 16; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				key := fmt.Sprintf("city-%d", (w*200+i)%500)
+				backend.Set(key, models.WeatherData{Name: key}, time.Minute)
+				if entry, found, _ := backend.Get(key); found && entry.Data.Name != key {
+					t.Errorf("Got %s for key %s", entry.Data.Name, key)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	entries, _ := backend.Entries()
+	if len(entries) != 500 {
+		t.Errorf("Expected 500 entries across shards, got %d", len(entries))
+	}
+	if shards := backend.Stats()["shards"].(int); shards != 8 {
+		t.Errorf("Expected 8 shards, got %d", shards)
+	}
+}
+
+func TestShardedMemoryBackend_StatsReportConfiguredLimits(t *testing.T) {
+	// 1000 entries and 10000 bytes do not split evenly over 3 shards
+	backend := cache.NewShardedMemoryBackend(3, 1000, 10000)
+	defer backend.Close()
+
+	stats := backend.Stats()
+	if stats["max_entries"] != 1000 || stats["max_bytes"] != int64(10000) {
+		t.Errorf("Expected the configured limits, got max_entries=%v max_bytes=%v", stats["max_entries"], stats["max_bytes"])
+	}
+}
+
+func TestShardedMemoryBackend_LimitsSpanShards(t *testing.T) {
+	backend := cache.NewShardedMemoryBackend(4, 400, 0)
+	defer backend.Close()
+
+	for i := 0; i < 2000; i++ {
+		backend.Set(fmt.Sprintf("city-%d", i), models.WeatherData{}, time.Minute)
+	}
+
+	entries, _ := backend.Entries()
+	if len(entries) > 400 {
+		t.Errorf("Expected at most 400 entries, got %d", len(entries))
+	}
+	if backend.Evictions() != int64(2000-len(entries)) {
+		t.Errorf("Expected evictions to account for every dropped entry, got %d", backend.Evictions())
+	}
+}
+
+func BenchmarkMemoryBackend_ParallelGet(b *testing.B) {
+	for _, shards := range []int{1, 16} {
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			backend := cache.NewShardedMemoryBackend(shards, 0, 0)
+			defer backend.Close()
+			keys := make([]string, benchCities)
+			for i := range keys {
+				keys[i] = fmt.Sprintf("city-%d", i)
+				backend.Set(keys[i], models.WeatherData{Name: keys[i]}, time.Hour)
+			}
+
+			var seed atomic.Int64
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				i := int(seed.Add(1)) * 7919
+				for pb.Next() {
+					backend.Get(keys[i%benchCities])
+					i++
+				}
+			})
+		})
+	}
+}
+
+// BenchmarkWeatherHandler_Parallel measures cached /weather throughput
+// end to end through the handler, service and cache
+func BenchmarkWeatherHandler_Parallel(b *testing.B) {
+	for _, shards := range []int{1, 16} {
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			backend := cache.NewShardedMemoryBackend(shards, 0, 0)
+			cacheManager := cache.NewCacheManagerWithBackend(time.Hour, backend)
+			defer cacheManager.Close()
+			metricsManager := metrics.NewMetricsManager()
+			// Every request is served from cache; the unreachable base URL
+			// keeps a stray upstream call from measuring the network instead
+			service := services.NewWeatherService(openweathermap.NewClientWithBaseURL("test_api_key", "http://127.0.0.1:1"), cacheManager, metricsManager)
+			handler := handlers.NewHandler(service, metricsManager, cacheManager)
+
+			// The seeded cities are all at 0,0 and share its readings
+			point := location.DefaultKeyer().Key(0, 0)
+			cacheManager.Set("uv:"+point, models.WeatherData{UVIndex: 1})
+			cacheManager.Set("aqi:"+point, models.WeatherData{AQI: 1, AirQuality: "Good"})
+			urls := make([]string, benchCities)
+			for i := range urls {
+				city := fmt.Sprintf("city-%d", i)
+				cacheManager.Set(city, models.WeatherData{Name: city})
+				urls[i] = "/weather?city=" + city
+			}
+
+			var seed atomic.Int64
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				i := int(seed.Add(1)) * 7919
+				for pb.Next() {
+					rec := httptest.NewRecorder()
+					handler.WeatherHandler(rec, httptest.NewRequest(http.MethodGet, urls[i%benchCities], nil))
+					if rec.Code != http.StatusOK {
+						b.Fatalf("Unexpected status %d", rec.Code)
+					}
+					i++
+				}
+			})
+		})
+	}
+}
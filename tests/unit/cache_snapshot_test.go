@@ -0,0 +1,102 @@
+package unit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/cache"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/models"
+)
+
+func TestCacheManager_SnapshotRoundTrip(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "cache.snapshot")
+
+	before := cache.NewCacheManager(10 * time.Minute)
+	before.Set("london", models.WeatherData{Name: "London", Country: "GB"})
+	before.Set("paris", models.WeatherData{Name: "Paris", Country: "FR"})
+	londonExpiry, _ := before.ExpiresAt("london")
+
+	saved, err := before.SaveSnapshot(filename)
+	if err != nil || saved != 2 {
+		t.Fatalf("SaveSnapshot() = %d, %v; want 2 entries", saved, err)
+	}
+
+	after := cache.NewCacheManager(10 * time.Minute)
+	restored, err := after.LoadSnapshot(filename)
+	if err != nil || restored != 2 {
+		t.Fatalf("LoadSnapshot() = %d, %v; want 2 entries", restored, err)
+	}
+
+	data, found := after.Get("london")
+	if !found || data.Country != "GB" {
+		t.Fatalf("Expected London to be restored, got %+v", data)
+	}
+	if diff := data.ExpiresAt.Sub(londonExpiry); diff > time.Second || diff < -time.Second {
+		t.Errorf("Expected original expiry to be kept, off by %v", diff)
+	}
+}
+
+func TestCacheManager_SnapshotSkipsExpired(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "cache.snapshot")
+
+	before := cache.NewCacheManager(100 * time.Millisecond)
+	before.Set("london", models.WeatherData{Name: "London"})
+	if _, err := before.SaveSnapshot(filename); err != nil {
+		t.Fatalf("SaveSnapshot() error = %v", err)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+
+	after := cache.NewCacheManager(10 * time.Minute)
+	restored, err := after.LoadSnapshot(filename)
+	if err != nil {
+		t.Fatalf("LoadSnapshot() error = %v", err)
+	}
+	if restored != 0 || after.GetSize() != 0 {
+		t.Errorf("Expected expired entries to be skipped, restored %d", restored)
+	}
+}
+
+func TestCacheManager_SnapshotVersionMismatch(t *testing.T) {
+	dir := t.TempDir()
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		snapshot map[string]interface{}
+	}{
+		{"Unknown version", map[string]interface{}{"version": 99, "entries": []interface{}{}}},
+		{"Schema changed", map[string]interface{}{
+			"version": 1,
+			"schema":  "0000000000000000",
+			"entries": []map[string]interface{}{{"key": "london", "expires_at": future, "data": map[string]string{"name": "London"}}},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(dir, tt.name)
+			bytes, _ := json.Marshal(tt.snapshot)
+			os.WriteFile(filename, bytes, 0600)
+
+			cm := cache.NewCacheManager(10 * time.Minute)
+			if _, err := cm.LoadSnapshot(filename); err == nil {
+				t.Error("Expected incompatible snapshot to be rejected")
+			}
+			if cm.GetSize() != 0 {
+				t.Error("Expected nothing to be restored from an incompatible snapshot")
+			}
+		})
+	}
+}
+
+func TestCacheManager_SnapshotMissingFile(t *testing.T) {
+	cm := cache.NewCacheManager(10 * time.Minute)
+	restored, err := cm.LoadSnapshot(filepath.Join(t.TempDir(), "missing"))
+	if err != nil || restored != 0 {
+		t.Errorf("Expected missing snapshot to be a no-op, got %d, %v", restored, err)
+	}
+}
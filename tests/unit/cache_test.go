@@ -1,6 +1,8 @@
 package unit
 
 import (
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 	"github.com/Vivek-Prakash1307/weather-Microservices/internal/cache"
@@ -108,6 +110,55 @@ func TestCacheManager_Stats(t *testing.T) {
 	}
 }
 
+// TestCacheManager_ConcurrentStats hammers the cache from many goroutines
+// while reading stats. Run with -race to catch unsynchronized counters.
+func TestCacheManager_ConcurrentStats(t *testing.T) {
+	cm := cache.NewCacheManager(10 * time.Minute)
+	cm.Set("hot", models.WeatherData{Name: "Hot"})
+
+	const (
+		workers    = 32
+		iterations = 500
+	)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				switch i % 4 {
+				case 0:
+					cm.Get("hot") // hit
+				case 1:
+					cm.Get(fmt.Sprintf("cold-%d-%d", w, i)) // miss
+				case 2:
+					cm.Set(fmt.Sprintf("city-%d", i%10), models.WeatherData{Name: "City"})
+				case 3:
+					cm.GetStats()
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	stats := cm.GetStats()
+	hits := stats["hit_count"].(int64)
+	misses := stats["miss_count"].(int64)
+	wantHits := int64(workers * iterations / 4)
+	wantMisses := int64(workers * iterations / 4)
+
+	if hits != wantHits {
+		t.Errorf("Expected %d hits, got %d", wantHits, hits)
+	}
+	if misses != wantMisses {
+		t.Errorf("Expected %d misses, got %d", wantMisses, misses)
+	}
+	if hitRate := stats["hit_rate"].(float64); hitRate != 50.0 {
+		t.Errorf("Expected hit rate 50%%, got %.2f%%", hitRate)
+	}
+}
+
 func BenchmarkCacheManager_Set(b *testing.B) {
 	cm := cache.NewCacheManager(10 * time.Minute)
 	testData := models.WeatherData{Name: "TestCity"}
@@ -127,4 +178,18 @@ func BenchmarkCacheManager_Get(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		cm.Get("testcity")
 	}
+}
+
+func TestCacheManager_Keys(t *testing.T) {
+	cm := cache.NewCacheManager(10 * time.Minute)
+	cm.Set("paris", models.WeatherData{Name: "Paris"})
+	cm.Set("london", models.WeatherData{Name: "London"})
+
+	keys, err := cm.Keys()
+	if err != nil {
+		t.Fatalf("Keys() error = %v", err)
+	}
+	if len(keys) != 2 || keys[0] != "london" || keys[1] != "paris" {
+		t.Errorf("Expected sorted keys [london paris], got %v", keys)
+	}
 }
\ No newline at end of file
@@ -0,0 +1,131 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/cache"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/models"
+	"github.com/alicebob/miniredis/v2"
+)
+
+func newTieredReplica(t *testing.T, server *miniredis.Miniredis, l1TTL time.Duration) *cache.TieredBackend {
+	t.Helper()
+	l2, err := cache.NewRedisBackend(cache.RedisOptions{Addr: server.Addr()})
+	if err != nil {
+		t.Fatalf("NewRedisBackend() error = %v", err)
+	}
+	tiered, err := cache.NewTieredBackend(cache.NewMemoryBackend(), l1TTL, l2)
+	if err != nil {
+		t.Fatalf("NewTieredBackend() error = %v", err)
+	}
+	t.Cleanup(func() { tiered.Close() })
+	return tiered
+}
+
+// waitFor polls cond until it holds or the deadline passes
+func waitFor(t *testing.T, cond func() bool) bool {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+func TestTieredBackend_HitsPerTier(t *testing.T) {
+	server := miniredis.RunT(t)
+	writer := newTieredReplica(t, server, time.Minute)
+	reader := newTieredReplica(t, server, time.Minute)
+
+	writer.Set("london", models.WeatherData{Name: "London"}, 10*time.Minute)
+
+	reader.Get("london") // L2 hit, promoted to L1
+	reader.Get("london") // L1 hit
+	reader.Get("paris")  // miss
+
+	stats := reader.Stats()["tiers"].(map[string]interface{})
+	if stats["l1_hits"].(int64) != 1 || stats["l2_hits"].(int64) != 1 || stats["misses"].(int64) != 1 {
+		t.Errorf("Unexpected tier stats: %v", stats)
+	}
+
+	entry, _, _ := reader.Get("london")
+	if remaining := time.Until(entry.ExpiresAt); remaining < 9*time.Minute {
+		t.Errorf("Expected L1 to keep the L2 expiry, got %v remaining", remaining)
+	}
+}
+
+func TestTieredBackend_L1ExpiresBeforeL2(t *testing.T) {
+	server := miniredis.RunT(t)
+	tiered := newTieredReplica(t, server, 50*time.Millisecond)
+
+	tiered.Set("london", models.WeatherData{Name: "London"}, 10*time.Minute)
+	time.Sleep(80 * time.Millisecond)
+
+	if _, found, _ := tiered.Get("london"); !found {
+		t.Fatal("Expected entry to still be served from L2")
+	}
+	if hits := tiered.Stats()["tiers"].(map[string]interface{})["l2_hits"].(int64); hits != 1 {
+		t.Errorf("Expected the read to fall through to L2, got %d L2 hits", hits)
+	}
+}
+
+func TestTieredBackend_InvalidationBroadcast(t *testing.T) {
+	server := miniredis.RunT(t)
+	replicaA := newTieredReplica(t, server, time.Minute)
+	replicaB := newTieredReplica(t, server, time.Minute)
+
+	replicaA.Set("london", models.WeatherData{Name: "London"}, 10*time.Minute)
+	replicaA.Set("paris", models.WeatherData{Name: "Paris"}, 10*time.Minute)
+	replicaB.Get("london")
+	replicaB.Get("paris")
+
+	// Replica B has London in L1; a delete on A must evict it there too
+	replicaA.Delete("london")
+	if !waitFor(t, func() bool { _, found, _ := replicaB.Get("london"); return !found }) {
+		t.Error("Expected replica B to drop its L1 copy after delete")
+	}
+
+	replicaA.Clear()
+	if !waitFor(t, func() bool { _, found, _ := replicaB.Get("paris"); return !found }) {
+		t.Error("Expected replica B to drop its L1 after clear")
+	}
+}
+
+func TestTieredBackend_OverwriteBroadcast(t *testing.T) {
+	server := miniredis.RunT(t)
+	replicaA := newTieredReplica(t, server, time.Minute)
+	replicaB := newTieredReplica(t, server, time.Minute)
+
+	replicaA.Set("london", models.WeatherData{Name: "Stale"}, 10*time.Minute)
+	replicaB.Get("london")
+
+	// Replica B has the old value in L1; an overwrite on A must not leave it
+	// serving that copy until the L1 TTL runs out
+	replicaA.Set("london", models.WeatherData{Name: "London"}, 10*time.Minute)
+	if !waitFor(t, func() bool { entry, _, _ := replicaB.Get("london"); return entry.Data.Name == "London" }) {
+		t.Error("Expected replica B to read the new value after an overwrite")
+	}
+	if entry, found, _ := replicaA.Get("london"); !found || entry.Data.Name != "London" {
+		t.Errorf("Expected the writer to ignore its own invalidation, got %+v", entry.Data)
+	}
+}
+
+func TestCacheManager_TierStats(t *testing.T) {
+	server := miniredis.RunT(t)
+	cm := cache.NewCacheManagerWithBackend(10*time.Minute, newTieredReplica(t, server, time.Minute))
+	cm.Set("london", models.WeatherData{Name: "London"})
+	cm.Get("london")
+
+	backendStats, _ := cm.GetStats()["backend"].(map[string]interface{})
+	tiers, ok := backendStats["tiers"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Expected tier statistics in GetStats")
+	}
+	if tiers["l1_hits"].(int64) != 1 {
+		t.Errorf("Expected 1 L1 hit, got %v", tiers["l1_hits"])
+	}
+}
@@ -0,0 +1,113 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/api/openweathermap"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/cache"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/metrics"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/services"
+)
+
+func TestWeatherService_PerDataTypeTTLs(t *testing.T) {
+	upstream := newFakeUpstream(t)
+	cacheManager := cache.NewCacheManager(50 * time.Millisecond)
+	service := services.NewWeatherService(openweathermap.NewClientWithBaseURL("test_api_key", upstream.URL), cacheManager, metrics.NewMetricsManager())
+	service.SetDataTTLs(time.Hour, 30*time.Minute)
+
+	first, err := service.GetWeatherData("London")
+	if err != nil {
+		t.Fatalf("GetWeatherData() error = %v", err)
+	}
+	if first.UVIndex != 4.5 || first.AQI != 2 {
+		t.Errorf("Expected UV 4.5 and AQI 2, got %v and %d", first.UVIndex, first.AQI)
+	}
+
+	// Once the weather expires only the weather is refetched
+	time.Sleep(80 * time.Millisecond)
+	second, err := service.GetWeatherData("London")
+	if err != nil {
+		t.Fatalf("GetWeatherData() error = %v", err)
+	}
+	if second.UVIndex != 4.5 || second.AQI != 2 {
+		t.Errorf("Expected cached UV and AQI to be reused, got %v and %d", second.UVIndex, second.AQI)
+	}
+
+	tests := []struct {
+		endpoint string
+		expected int
+	}{
+		{"weather", 2},
+		{"uvi", 1},
+		{"air_pollution", 1},
+	}
+	for _, tt := range tests {
+		if calls := upstream.Calls(tt.endpoint); calls != tt.expected {
+			t.Errorf("Expected %d calls to /%s, got %d", tt.expected, tt.endpoint, calls)
+		}
+	}
+}
+
+func TestWeatherService_ReadingsMergedOnRead(t *testing.T) {
+	upstream := newFakeUpstream(t)
+	cacheManager := cache.NewCacheManager(10 * time.Minute)
+	service := services.NewWeatherService(openweathermap.NewClientWithBaseURL("test_api_key", upstream.URL), cacheManager, metrics.NewMetricsManager())
+	service.SetDataTTLs(50*time.Millisecond, 30*time.Minute)
+
+	data, err := service.GetWeatherData("London")
+	if err != nil {
+		t.Fatalf("GetWeatherData() error = %v", err)
+	}
+	if remaining := time.Until(data.ExpiresAt); remaining > time.Second {
+		t.Errorf("Expected the response to expire with the UV reading, got %v remaining", remaining)
+	}
+	if entry, _ := cacheManager.Peek("london"); time.Until(entry.ExpiresAt) < 9*time.Minute {
+		t.Errorf("Expected the weather entry to keep the weather TTL, got %v remaining", time.Until(entry.ExpiresAt))
+	}
+
+	// Once the UV reading expires it is fetched again on its own, and merged
+	// into the weather that is still cached
+	time.Sleep(80 * time.Millisecond)
+	data, err = service.GetWeatherData("London")
+	if err != nil {
+		t.Fatalf("GetWeatherData() error = %v", err)
+	}
+	if !data.CacheHit || data.UVIndex != 4.5 || data.AQI != 2 {
+		t.Errorf("Expected cached weather with fresh readings, got hit=%v UV %v AQI %d", data.CacheHit, data.UVIndex, data.AQI)
+	}
+	if weather, uv := upstream.Calls("weather"), upstream.Calls("uvi"); weather != 1 || uv != 2 {
+		t.Errorf("Expected 1 weather and 2 UV calls, got %d and %d", weather, uv)
+	}
+}
+
+func TestWeatherService_FailedReadingsCached(t *testing.T) {
+	upstream := newFakeUpstream(t)
+	upstream.Fail("uvi")
+	upstream.Fail("air_pollution")
+	cacheManager := cache.NewCacheManager(10 * time.Minute)
+	service := services.NewWeatherService(openweathermap.NewClientWithBaseURL("test_api_key", upstream.URL), cacheManager, metrics.NewMetricsManager())
+
+	for i := 0; i < 3; i++ {
+		data, err := service.GetWeatherData("London")
+		if err != nil {
+			t.Fatalf("GetWeatherData() error = %v", err)
+		}
+		if data.UVIndex != -1 || data.AQI != -1 {
+			t.Errorf("Expected unknown readings, got UV %v AQI %d", data.UVIndex, data.AQI)
+		}
+		if remaining := time.Until(data.ExpiresAt); remaining > services.FailedReadingTTL {
+			t.Errorf("Expected the response to expire with the failed readings, got %v remaining", remaining)
+		}
+	}
+
+	// The failures are remembered, and reading them back is not counted as
+	// weather cache traffic
+	if uv, aqi := upstream.Calls("uvi"), upstream.Calls("air_pollution"); uv != 1 || aqi != 1 {
+		t.Errorf("Expected 1 UV and 1 air quality call, got %d and %d", uv, aqi)
+	}
+	stats := cacheManager.GetStats()
+	if stats["hit_count"] != int64(2) || stats["miss_count"] != int64(1) {
+		t.Errorf("Expected 2 hits and 1 miss, got %v and %v", stats["hit_count"], stats["miss_count"])
+	}
+}
\ No newline at end of file
@@ -0,0 +1,271 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/api/openweathermap"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/cache"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/handlers"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/metrics"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/models"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/services"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/tenant"
+	"github.com/gorilla/mux"
+)
+
+func newCacheAdminRouter(t *testing.T) (*mux.Router, *cache.CacheManager, *fakeUpstream) {
+	t.Helper()
+	upstream := newFakeUpstream(t)
+	cacheManager := cache.NewCacheManager(10 * time.Minute)
+	metricsManager := metrics.NewMetricsManager()
+	service := services.NewWeatherService(openweathermap.NewClientWithBaseURL("test_api_key", upstream.URL), cacheManager, metricsManager)
+	handler := handlers.NewHandler(service, metricsManager, cacheManager)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/cache", handler.CacheHandler).Methods("GET")
+	router.HandleFunc("/cache", handler.CacheInvalidateHandler).Methods("DELETE")
+	router.HandleFunc("/cache/clear", handler.CacheClearHandler).Methods("POST")
+	router.HandleFunc("/cache/{key}", handler.CacheEntryHandler).Methods("GET")
+	router.HandleFunc("/cache/{key}", handler.CacheDeleteHandler).Methods("DELETE")
+	router.HandleFunc("/cache/{key}/refresh", handler.CacheRefreshHandler).Methods("POST")
+	return router, cacheManager, upstream
+}
+
+func serve(router http.Handler, method, url string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(method, url, nil))
+	return rec
+}
+
+func TestCacheEntryHandler(t *testing.T) {
+	router, cacheManager, _ := newCacheAdminRouter(t)
+	cacheManager.Set("london", models.WeatherData{Name: "London"})
+	cacheManager.SetNotFound("lndon")
+
+	rec := serve(router, http.MethodGet, "/cache/london")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	var body map[string]interface{}
+	json.NewDecoder(rec.Body).Decode(&body)
+	for _, field := range []string{"expires_at", "expires_in_seconds", "stored_at", "age_seconds", "data"} {
+		if _, ok := body[field]; !ok {
+			t.Errorf("Expected %s in response", field)
+		}
+	}
+
+	if rec := serve(router, http.MethodGet, "/cache/lndon"); rec.Code != http.StatusOK {
+		t.Errorf("Expected negative entry to be reported, got %d", rec.Code)
+	}
+	if rec := serve(router, http.MethodGet, "/cache/paris"); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for missing entry, got %d", rec.Code)
+	}
+	if rec := serve(router, http.MethodDelete, "/cache/lndon"); rec.Code != http.StatusOK {
+		t.Errorf("Expected negative entry to be deleted, got %d", rec.Code)
+	}
+
+	// Inspecting negative entries is not a negative hit
+	if hits := cacheManager.GetStats()["negative_hit_count"]; hits != int64(0) {
+		t.Errorf("Expected no negative hits, got %v", hits)
+	}
+}
+
+func TestCacheDeleteHandler(t *testing.T) {
+	router, cacheManager, _ := newCacheAdminRouter(t)
+	cacheManager.Set("london", models.WeatherData{Name: "London"})
+
+	if rec := serve(router, http.MethodDelete, "/cache/london"); rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	if _, found := cacheManager.Peek("london"); found {
+		t.Error("Expected entry to be deleted")
+	}
+	if rec := serve(router, http.MethodDelete, "/cache/london"); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for missing entry, got %d", rec.Code)
+	}
+}
+
+func TestCacheRefreshHandler(t *testing.T) {
+	router, cacheManager, upstream := newCacheAdminRouter(t)
+	cacheManager.Set("london", models.WeatherData{Name: "Stale"})
+
+	tests := []struct {
+		name         string
+		key          string
+		expectedCode int
+	}{
+		{"City", "london", http.StatusOK},
+		{"Coordinates", "grid:51.51,-0.13", http.StatusOK},
+		{"UV reading", "uv:grid:51.51,-0.13", http.StatusOK},
+		{"Unknown city", "lndon", http.StatusNotFound},
+		{"Unknown key format", "other:london", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := serve(router, http.MethodPost, "/cache/"+tt.key+"/refresh"); rec.Code != tt.expectedCode {
+				t.Errorf("Expected status %d, got %d", tt.expectedCode, rec.Code)
+			}
+		})
+	}
+
+	if data, _ := cacheManager.Peek("london"); data.Data.Name != "London" {
+		t.Errorf("Expected refreshed data, got %q", data.Data.Name)
+	}
+	if calls := upstream.Calls("uvi"); calls != 2 {
+		t.Errorf("Expected the UV refresh to go upstream, got %d calls", calls)
+	}
+}
+
+func TestWeatherService_RefreshOutsideNamespace(t *testing.T) {
+	cacheManager := cache.NewCacheManager(10 * time.Minute)
+	service := services.NewWeatherService(openweathermap.NewClient("test_api_key"), cacheManager, metrics.NewMetricsManager())
+
+	acme := &tenant.Tenant{ID: "acme", CacheNamespace: "acme"}
+	if _, err := service.RefreshCacheEntry(acme, "globex:london"); err == nil {
+		t.Error("Expected refreshing another tenant's key to fail")
+	}
+}
+
+func TestWeatherService_RefreshKeepsEntryOnFailure(t *testing.T) {
+	cacheManager := cache.NewCacheManager(10 * time.Minute)
+	service := services.NewWeatherService(openweathermap.NewClientWithBaseURL("test_api_key", "http://127.0.0.1:1"), cacheManager, metrics.NewMetricsManager())
+	cacheManager.Set("london", models.WeatherData{Name: "Stale"})
+
+	if _, err := service.RefreshCacheEntry(nil, "london"); err == nil {
+		t.Fatal("Expected the refresh to fail while upstream is unreachable")
+	}
+	if entry, found := cacheManager.Peek("london"); !found || entry.Data.Name != "Stale" {
+		t.Errorf("Expected the cached entry to survive a failed refresh, got %+v (found=%v)", entry.Data, found)
+	}
+}
+
+func TestCacheInvalidateHandler(t *testing.T) {
+	router, cacheManager, _ := newCacheAdminRouter(t)
+	for _, key := range []string{"acme:london", "acme:paris", "globex:london", "uv:grid:51.51,-0.13"} {
+		cacheManager.Set(key, models.WeatherData{})
+	}
+
+	tests := []struct {
+		name            string
+		url             string
+		expectedCode    int
+		expectedDeleted float64
+	}{
+		{"Prefix", "/cache?prefix=acme:", http.StatusOK, 2},
+		{"Pattern", "/cache?pattern=uv:*", http.StatusOK, 1},
+		{"Invalid pattern", "/cache?pattern=[", http.StatusBadRequest, 0},
+		{"Missing parameter", "/cache", http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(router, http.MethodDelete, tt.url)
+			if rec.Code != tt.expectedCode {
+				t.Fatalf("Expected status %d, got %d", tt.expectedCode, rec.Code)
+			}
+			if tt.expectedCode != http.StatusOK {
+				return
+			}
+			var body map[string]interface{}
+			json.NewDecoder(rec.Body).Decode(&body)
+			if body["deleted"] != tt.expectedDeleted {
+				t.Errorf("Expected %v deleted, got %v", tt.expectedDeleted, body["deleted"])
+			}
+		})
+	}
+
+	if cacheManager.GetSize() != 1 {
+		t.Errorf("Expected only globex:london to remain, got %d entries", cacheManager.GetSize())
+	}
+}
+
+func TestCacheAdminHandlers_TenantNamespace(t *testing.T) {
+	router, cacheManager, _ := newCacheAdminRouter(t)
+	for _, key := range []string{"acme:london", "globex:london", "globex:paris"} {
+		cacheManager.Set(key, models.WeatherData{})
+	}
+	acme := &tenant.Tenant{ID: "acme", CacheNamespace: "acme"}
+	serveAs := func(method, url string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req.WithContext(tenant.WithTenant(req.Context(), acme)))
+		return rec
+	}
+
+	tests := []struct {
+		name         string
+		method       string
+		url          string
+		expectedCode int
+	}{
+		{"Read own entry", http.MethodGet, "/cache/acme:london", http.StatusOK},
+		{"Read other tenant's entry", http.MethodGet, "/cache/globex:london", http.StatusForbidden},
+		{"Delete other tenant's entry", http.MethodDelete, "/cache/globex:london", http.StatusForbidden},
+		{"Invalidate other tenant's prefix", http.MethodDelete, "/cache?prefix=globex:", http.StatusForbidden},
+		{"Invalidate everything by prefix", http.MethodDelete, "/cache?prefix=", http.StatusBadRequest},
+		{"Pattern reaching other tenants", http.MethodDelete, "/cache?pattern=*", http.StatusOK},
+		{"Refresh other tenant's entry", http.MethodPost, "/cache/globex:london/refresh", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := serveAs(tt.method, tt.url); rec.Code != tt.expectedCode {
+				t.Errorf("Expected status %d, got %d", tt.expectedCode, rec.Code)
+			}
+		})
+	}
+
+	if keys, _ := cacheManager.Keys(); len(keys) != 2 || keys[0] != "globex:london" || keys[1] != "globex:paris" {
+		t.Errorf("Expected only acme's entry to be removed, got %v", keys)
+	}
+}
+
+func TestCacheHandlers_TenantNamespace(t *testing.T) {
+	router, cacheManager, _ := newCacheAdminRouter(t)
+	for _, key := range []string{"acme:london", "acme:paris", "globex:london"} {
+		cacheManager.Set(key, models.WeatherData{})
+	}
+	cacheManager.SetNotFound("acme:lndon")
+	cacheManager.SetNotFound("globex:lndon")
+	acme := &tenant.Tenant{ID: "acme", CacheNamespace: "acme"}
+	serveAs := func(method, url string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req.WithContext(tenant.WithTenant(req.Context(), acme)))
+		return rec
+	}
+
+	var stats map[string]interface{}
+	json.NewDecoder(serveAs(http.MethodGet, "/cache").Body).Decode(&stats)
+	entries, _ := stats["entries"].(map[string]interface{})
+	if _, found := entries["globex:london"]; found || len(entries) != 2 || stats["total_entries"] != 2.0 {
+		t.Errorf("Expected only acme's entries to be listed, got %v", stats["entries"])
+	}
+	if stats["negative_entries"] != 1.0 || stats["namespace"] != "acme:" {
+		t.Errorf("Expected acme's negative entry and namespace, got %v", stats)
+	}
+	for _, shared := range []string{"backend", "hit_count", "miss_count", "hit_rate", "negative_hit_count"} {
+		if _, found := stats[shared]; found {
+			t.Errorf("Expected the shared %s to be left out", shared)
+		}
+	}
+
+	if rec := serveAs(http.MethodPost, "/cache/clear?type=negative"); rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	if cacheManager.IsNotFound("acme:lndon") || !cacheManager.IsNotFound("globex:lndon") {
+		t.Error("Expected only acme's negative entry to be cleared")
+	}
+
+	if rec := serveAs(http.MethodPost, "/cache/clear"); rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	if keys, _ := cacheManager.Keys(); len(keys) != 1 || keys[0] != "globex:london" {
+		t.Errorf("Expected only acme's entries to be cleared, got %v", keys)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,191 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/api/openweathermap"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/cache"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/handlers"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/metrics"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/server"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/services"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/tenant"
+	"github.com/Vivek-Prakash1307/weather-Microservices/pkg/client"
+)
+
+// newAPIServer serves the real router backed by a fake upstream
+func newAPIServer(t *testing.T, registry *tenant.Registry) *httptest.Server {
+	t.Helper()
+	upstream := newFakeUpstream(t)
+	cacheManager := cache.NewCacheManager(10 * time.Minute)
+	metricsManager := metrics.NewMetricsManager()
+	service := services.NewWeatherService(openweathermap.NewClientWithBaseURL("test_api_key", upstream.URL), cacheManager, metricsManager)
+
+	router := server.NewRouter(server.Options{
+		Handler:  handlers.NewHandler(service, metricsManager, cacheManager),
+		Registry: registry,
+	})
+	api := httptest.NewServer(router)
+	t.Cleanup(api.Close)
+	return api
+}
+
+func newTestClient(t *testing.T, baseURL string, opts ...client.Option) *client.Client {
+	t.Helper()
+	c, err := client.New(baseURL, append([]client.Option{client.WithRetries(2, time.Millisecond)}, opts...)...)
+	if err != nil {
+		t.Fatalf("client.New() error = %v", err)
+	}
+	return c
+}
+
+func TestClient_Weather(t *testing.T) {
+	c := newTestClient(t, newAPIServer(t, nil).URL)
+	ctx := context.Background()
+
+	data, err := c.Weather(ctx, "London")
+	if err != nil {
+		t.Fatalf("Weather() error = %v", err)
+	}
+	if data.Name != "London" || data.Country != "GB" || data.UVIndex != 4.5 || data.AQI != 2 {
+		t.Errorf("Unexpected weather data: %+v", data)
+	}
+
+	data, err = c.WeatherByCoords(ctx, 51.5085, -0.1257)
+	if err != nil || data.Name != "London" {
+		t.Errorf("Expected London by coordinates, got %+v (err=%v)", data, err)
+	}
+
+	health, err := c.Health(ctx)
+	if err != nil || health.Status != "healthy" {
+		t.Errorf("Expected healthy status, got %+v (err=%v)", health, err)
+	}
+}
+
+func TestClient_TypedErrors(t *testing.T) {
+	c := newTestClient(t, newAPIServer(t, nil).URL)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		call     func() error
+		sentinel error
+		status   int
+	}{
+		{"Unknown city", func() error { _, err := c.Weather(ctx, "lndon"); return err }, client.ErrNotFound, http.StatusNotFound},
+		{"Invalid coordinates", func() error { _, err := c.WeatherByCoords(ctx, 100, 0); return err }, client.ErrBadRequest, http.StatusBadRequest},
+		{"Missing cache entry", func() error { return c.DeleteCacheEntry(ctx, "nowhere") }, client.ErrNotFound, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if !errors.Is(err, tt.sentinel) {
+				t.Errorf("Expected %v, got %v", tt.sentinel, err)
+			}
+			var apiErr *client.APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status || apiErr.Message == "" {
+				t.Errorf("Expected *client.APIError with status %d and a message, got %v", tt.status, err)
+			}
+		})
+	}
+}
+
+func TestClient_CacheAdmin(t *testing.T) {
+	c := newTestClient(t, newAPIServer(t, nil).URL)
+	ctx := context.Background()
+	c.Weather(ctx, "London")
+
+	entry, err := c.CacheEntry(ctx, "london")
+	if err != nil || entry.Data == nil || entry.Data.Name != "London" || entry.ExpiresAt.IsZero() {
+		t.Fatalf("Expected cached London entry, got %+v (err=%v)", entry, err)
+	}
+
+	refreshed, err := c.RefreshCacheEntry(ctx, "london")
+	if err != nil || refreshed.Name != "London" {
+		t.Errorf("Expected refreshed London, got %+v (err=%v)", refreshed, err)
+	}
+
+	removed, err := c.InvalidatePrefix(ctx, "lon")
+	if err != nil || removed != 1 {
+		t.Errorf("Expected 1 entry removed, got %d (err=%v)", removed, err)
+	}
+}
+
+func TestClient_APIKey(t *testing.T) {
+	registry := tenant.NewRegistry([]*tenant.Tenant{
+		{ID: "team-a", Credentials: []string{"key-a"}, CacheNamespace: "a"},
+	}, &tenant.Tenant{ID: tenant.DefaultID})
+	api := newAPIServer(t, registry)
+	ctx := context.Background()
+
+	if _, err := newTestClient(t, api.URL).Weather(ctx, "London"); !errors.Is(err, client.ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized without an API key, got %v", err)
+	}
+	if _, err := newTestClient(t, api.URL, client.WithAPIKey("key-a")).Weather(ctx, "London"); err != nil {
+		t.Errorf("Expected tenant key to be accepted, got %v", err)
+	}
+}
+
+func TestClient_Retries(t *testing.T) {
+	api := newAPIServer(t, nil)
+	var attempts atomic.Int32
+	flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		api.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer flaky.Close()
+
+	if _, err := newTestClient(t, flaky.URL).Weather(context.Background(), "London"); err != nil {
+		t.Fatalf("Expected retries to succeed, got %v", err)
+	}
+	if attempts.Load() != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts.Load())
+	}
+
+	// Without retries, and for non-idempotent requests, one failure is final
+	var downAttempts atomic.Int32
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downAttempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+
+	noRetry := newTestClient(t, down.URL, client.WithRetries(0, 0))
+	if _, err := noRetry.Weather(context.Background(), "London"); !errors.Is(err, client.ErrServer) {
+		t.Errorf("Expected ErrServer without retries, got %v", err)
+	}
+	if err := newTestClient(t, down.URL).ClearCache(context.Background()); !errors.Is(err, client.ErrServer) {
+		t.Errorf("Expected POST to fail without retrying, got %v", err)
+	}
+	if downAttempts.Load() != 2 {
+		t.Errorf("Expected one attempt per request, got %d", downAttempts.Load())
+	}
+}
+
+func TestClient_Context(t *testing.T) {
+	c := newTestClient(t, newAPIServer(t, nil).URL)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := c.Weather(ctx, "London"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestClient_InvalidBaseURL(t *testing.T) {
+	for _, baseURL := range []string{"", "localhost:8080", "ftp://weather"} {
+		if _, err := client.New(baseURL); err == nil {
+			t.Errorf("Expected base URL %q to be rejected", baseURL)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package unit
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/middleware"
+	"github.com/andybalholm/brotli"
+)
+
+func compressionHandler(contentType, body string) http.Handler {
+	return middleware.CompressionMiddleware(middleware.DefaultCompressionMinSize)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", contentType)
+			io.WriteString(w, body)
+		}),
+	)
+}
+
+func TestCompressionMiddleware(t *testing.T) {
+	largeJSON := `{"name":"` + strings.Repeat("London", 500) + `"}`
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		contentType    string
+		body           string
+		wantEncoding   string
+	}{
+		{"Gzip", "gzip", "application/json", largeJSON, "gzip"},
+		{"Brotli preferred", "gzip, deflate, br", "application/json", largeJSON, "br"},
+		{"Q-values respected", "br;q=0.5, gzip;q=0.9", "application/json", largeJSON, "gzip"},
+		{"Encoding refused", "br;q=0, gzip;q=0", "application/json", largeJSON, ""},
+		{"Wildcard", "*", "application/json", largeJSON, "br"},
+		{"Wildcard skips refused encoding", "gzip;q=0.5, br;q=0, *", "application/json", largeJSON, "gzip"},
+		{"Wildcard covers unlisted encoding", "gzip;q=0.5, *;q=0.8", "application/json", largeJSON, "br"},
+		{"Wildcard refused", "*;q=0", "application/json", largeJSON, ""},
+		{"No Accept-Encoding", "", "application/json", largeJSON, ""},
+		{"Tiny response", "gzip", "application/json", `{"ok":true}`, ""},
+		{"Already compressed type", "gzip", "image/png", largeJSON, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/weather", nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			rec := httptest.NewRecorder()
+			compressionHandler(tt.contentType, tt.body).ServeHTTP(rec, req)
+
+			if got := rec.Header().Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Fatalf("Expected Content-Encoding %q, got %q", tt.wantEncoding, got)
+			}
+			if got := rec.Header().Get("Vary"); got != "Accept-Encoding" {
+				t.Errorf("Expected Vary: Accept-Encoding, got %q", got)
+			}
+
+			var reader io.Reader = rec.Body
+			switch tt.wantEncoding {
+			case "gzip":
+				gr, err := gzip.NewReader(rec.Body)
+				if err != nil {
+					t.Fatalf("invalid gzip body: %v", err)
+				}
+				reader = gr
+			case "br":
+				reader = brotli.NewReader(rec.Body)
+			}
+			body, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("failed to read body: %v", err)
+			}
+			if string(body) != tt.body {
+				t.Errorf("Decoded body does not match original")
+			}
+		})
+	}
+}
+
+func TestCompressionMiddleware_Flush(t *testing.T) {
+	handler := middleware.CompressionMiddleware(middleware.DefaultCompressionMinSize)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			io.WriteString(w, "data: first\n\n")
+			if err := http.NewResponseController(w).Flush(); err != nil {
+				t.Errorf("Flush() error = %v", err)
+			}
+		}),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/stream", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if !rec.Flushed {
+		t.Error("Expected the underlying writer to be flushed")
+	}
+	if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Errorf("Expected flushed stream to be gzip encoded, got %q", got)
+	}
+}
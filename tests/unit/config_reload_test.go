@@ -0,0 +1,173 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/config"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/logging"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/middleware"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/tenant"
+)
+
+// newReloadWatcher loads filename and watches it, recording every applied reload
+func newReloadWatcher(t *testing.T, filename string) (*config.Watcher, *int) {
+	t.Helper()
+	cfg, err := config.LoadConfig(filename)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	applied := 0
+	watcher := config.NewWatcher(filename, cfg, func(old, updated *config.Config) { applied++ })
+	return watcher, &applied
+}
+
+func TestWatcher_ReloadAppliesChanges(t *testing.T) {
+	filename := writeConfig(t, "config.json", `{"OpenWeatherMapApiKey": "old_key", "CacheExpiryMinutes": 10}`)
+	watcher, applied := newReloadWatcher(t, filename)
+
+	os.WriteFile(filename, []byte(`{"OpenWeatherMapApiKey": "new_key", "CacheExpiryMinutes": 5, "ServerPort": "9090"}`), 0600)
+	changes, err := watcher.Reload()
+	if err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	if *applied != 1 {
+		t.Errorf("Expected apply to run once, ran %d times", *applied)
+	}
+	if watcher.Current().CacheExpiryMinutes != 5 {
+		t.Errorf("Expected cache expiry 5, got %d", watcher.Current().CacheExpiryMinutes)
+	}
+	if watcher.Current().ServerPort != "8080" {
+		t.Errorf("Expected the port in use to stay active until a restart, got %s", watcher.Current().ServerPort)
+	}
+
+	diff := strings.Join(changes, "\n")
+	for _, expected := range []string{
+		"CacheExpiryMinutes: 10 -> 5",
+		"ServerPort: 8080 -> 9090 (requires restart)",
+		"OpenWeatherMapApiKey: changed",
+	} {
+		if !strings.Contains(diff, expected) {
+			t.Errorf("Expected diff to contain %q, got:\n%s", expected, diff)
+		}
+	}
+	if strings.Contains(diff, "new_key") || strings.Contains(diff, "old_key") {
+		t.Errorf("Expected diff to redact secrets, got:\n%s", diff)
+	}
+}
+
+func TestWatcher_RejectsInvalidReload(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"Malformed JSON", `{"OpenWeatherMapApiKey": `},
+		{"Unknown log level", `{"OpenWeatherMapApiKey": "key", "LogLevel": "verbose"}`},
+		{"Missing API key", `{"CacheExpiryMinutes": 5}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := writeConfig(t, "config.json", `{"OpenWeatherMapApiKey": "key", "CacheExpiryMinutes": 10}`)
+			watcher, applied := newReloadWatcher(t, filename)
+			previous := watcher.Current()
+
+			os.WriteFile(filename, []byte(tt.content), 0600)
+			if _, err := watcher.Reload(); err == nil {
+				t.Error("Expected reload to be rejected")
+			}
+			if *applied != 0 {
+				t.Errorf("Expected apply not to run, ran %d times", *applied)
+			}
+			if watcher.Current() != previous {
+				t.Error("Expected previous config to stay active")
+			}
+		})
+	}
+}
+
+func TestWatcher_RejectsMissingFile(t *testing.T) {
+	t.Setenv("WEATHER_API_KEY", "env_key")
+	filename := writeConfig(t, "config.json", `{"OpenWeatherMapApiKey": "key", "CacheExpiryMinutes": 5}`)
+	watcher, applied := newReloadWatcher(t, filename)
+
+	os.Remove(filename)
+	if _, err := watcher.Reload(); err == nil {
+		t.Error("Expected reload of a missing file to be rejected")
+	}
+	if *applied != 0 || watcher.Current().CacheExpiryMinutes != 5 {
+		t.Errorf("Expected previous config to stay active, apply ran %d times", *applied)
+	}
+}
+
+func TestWatcher_ReloadWithoutChanges(t *testing.T) {
+	filename := writeConfig(t, "config.json", `{"OpenWeatherMapApiKey": "key"}`)
+	watcher, applied := newReloadWatcher(t, filename)
+
+	changes, err := watcher.Reload()
+	if err != nil || len(changes) != 0 {
+		t.Errorf("Expected no changes, got %v (err=%v)", changes, err)
+	}
+	if *applied != 0 {
+		t.Errorf("Expected apply not to run, ran %d times", *applied)
+	}
+}
+
+func TestRateLimiter_SetLimit(t *testing.T) {
+	limiter := middleware.NewRateLimiter(1)
+	handler := limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	request := func() int {
+		req := httptest.NewRequest("GET", "/weather", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	request()
+	if code := request(); code != http.StatusTooManyRequests {
+		t.Fatalf("Expected 429 over the limit, got %d", code)
+	}
+
+	limiter.SetLimit(5)
+	if code := request(); code != http.StatusOK {
+		t.Errorf("Expected raised limit to admit the request, got %d", code)
+	}
+}
+
+func TestTenantRegistry_Replace(t *testing.T) {
+	registry := newTestRegistry()
+	registry.Replace([]*tenant.Tenant{{ID: "globex", Credentials: []string{"globex-key"}}}, &tenant.Tenant{ID: tenant.DefaultID})
+
+	if _, found := registry.Get("team-a"); found {
+		t.Error("Expected replaced tenant to be removed")
+	}
+	if _, found := registry.Get("globex"); !found {
+		t.Error("Expected new tenant to be registered")
+	}
+}
+
+func TestLogging_Levels(t *testing.T) {
+	defer logging.SetLevel(logging.CurrentLevel())
+
+	level, err := logging.ParseLevel("WARN")
+	if err != nil {
+		t.Fatalf("ParseLevel() error = %v", err)
+	}
+	logging.SetLevel(level)
+
+	if logging.Enabled(logging.Info) {
+		t.Error("Expected info to be disabled at warn level")
+	}
+	if !logging.Enabled(logging.Error) {
+		t.Error("Expected error to be enabled at warn level")
+	}
+	if _, err := logging.ParseLevel("verbose"); err == nil {
+		t.Error("Expected unknown level to be rejected")
+	}
+}
@@ -0,0 +1,122 @@
+package unit
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/api/openweathermap"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/cache"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/config"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/handlers"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/metrics"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/services"
+)
+
+func TestLoadConfig_SecretReferences(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "owm_key")
+	os.WriteFile(secretFile, []byte("file_secret\n"), 0600)
+	t.Setenv("OWM_KEY", "env_secret")
+	t.Setenv("REDIS_SECRET", "redis_secret")
+
+	filename := writeConfig(t, "config.json", fmt.Sprintf(`{
+		"OpenWeatherMapApiKey": "file:%s",
+		"Redis": {"Password": "env:REDIS_SECRET"},
+		"Tenants": [{"ID": "acme", "APIKeys": ["env:OWM_KEY"]}]
+	}`, secretFile))
+
+	cfg, err := config.LoadConfig(filename)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		actual   string
+		expected string
+	}{
+		{"File reference", cfg.OpenWeatherMapApiKey, "file_secret"},
+		{"Env reference", cfg.Redis.Password, "redis_secret"},
+		{"Tenant key reference", cfg.Tenants[0].APIKeys[0], "env_secret"},
+		{"Tenant inherits resolved key", cfg.Tenants[0].OpenWeatherMapApiKey, "file_secret"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.actual != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, tt.actual)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_UnresolvableSecret(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"Missing file", "file:/nonexistent/owm_key"},
+		{"Unset variable", "env:WEATHER_TEST_UNSET_SECRET"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := writeConfig(t, "config.json", fmt.Sprintf(`{"OpenWeatherMapApiKey": %q}`, tt.value))
+			_, err := config.LoadConfig(filename)
+			if err == nil || !strings.Contains(err.Error(), "OpenWeatherMapApiKey") {
+				t.Errorf("Expected error naming the field, got %v", err)
+			}
+		})
+	}
+}
+
+func TestConfig_Redacted(t *testing.T) {
+	cfg := &config.Config{
+		OpenWeatherMapApiKey: "top_secret",
+		LogLevel:             "info",
+		Redis:                config.RedisConfig{Password: "redis_secret"},
+		Tenants:              []config.TenantConfig{{ID: "acme", APIKeys: []string{"acme_secret"}}},
+	}
+
+	for _, rendered := range []string{cfg.String(), fmt.Sprintf("%+v", cfg), fmt.Sprintf("%v", *cfg), fmt.Sprintf("%#v", cfg)} {
+		for _, secret := range []string{"top_secret", "redis_secret", "acme_secret"} {
+			if strings.Contains(rendered, secret) {
+				t.Errorf("Expected %s to be redacted in %s", secret, rendered)
+			}
+		}
+	}
+	if cfg.Tenants[0].APIKeys[0] != "acme_secret" {
+		t.Error("Expected Redacted to leave the original config untouched")
+	}
+}
+
+func TestConfigHandler_Redacts(t *testing.T) {
+	cacheManager := cache.NewCacheManager(10 * time.Minute)
+	metricsManager := metrics.NewMetricsManager()
+	service := services.NewWeatherService(openweathermap.NewClient("test_api_key"), cacheManager, metricsManager)
+	handler := handlers.NewHandler(service, metricsManager, cacheManager)
+
+	cfg := &config.Config{OpenWeatherMapApiKey: "top_secret", LogLevel: "info"}
+	handler.SetConfigSource(func() *config.Config { return cfg })
+
+	rec := httptest.NewRecorder()
+	handler.ConfigHandler(rec, httptest.NewRequest(http.MethodGet, "/config", nil))
+
+	var body map[string]interface{}
+	json.NewDecoder(rec.Body).Decode(&body)
+	if body["OpenWeatherMapApiKey"] != "[REDACTED]" || body["LogLevel"] != "info" {
+		t.Errorf("Expected redacted config dump, got %v", body)
+	}
+}
+
+func TestClient_ErrorsRedactAPIKey(t *testing.T) {
+	client := openweathermap.NewClientWithBaseURL("top_secret", "http://127.0.0.1:1")
+	if _, err := client.GetWeather("London", openweathermap.Options{}); err == nil || strings.Contains(err.Error(), "top_secret") {
+		t.Errorf("Expected transport error without the API key, got %v", err)
+	}
+}
@@ -0,0 +1,214 @@
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/config"
+)
+
+// writeConfig writes content to name inside a temporary directory
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(filename, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	return filename
+}
+
+func TestLoadConfig_YAML(t *testing.T) {
+	filename := writeConfig(t, "config.yaml", `
+weather_api:
+  api_key: "yaml_key"
+  base_url: "http://localhost:9000/data/2.5"
+  cache_expiry_minutes: 5
+  rate_limit_per_minute: 30
+server:
+  port: "9090"
+  cors:
+    allowed_origins: ["https://app.example.com"]
+log:
+  level: "debug"
+cache:
+  backend: "memory"
+  uv_minutes: 90
+  location:
+    strategy: "geohash"
+tenants:
+  - id: "acme"
+    api_keys: ["acme-key"]
+`)
+
+	cfg, err := config.LoadConfig(filename)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		actual   interface{}
+		expected interface{}
+	}{
+		{"API key", cfg.OpenWeatherMapApiKey, "yaml_key"},
+		{"Base URL", cfg.BaseURL, "http://localhost:9000/data/2.5"},
+		{"Cache expiry", cfg.CacheExpiryMinutes, 5},
+		{"Rate limit", cfg.RateLimitPerMinute, 30},
+		{"Port", cfg.ServerPort, "9090"},
+		{"Log level", cfg.LogLevel, "debug"},
+		{"CORS origin", cfg.CORS.AllowedOrigins[0], "https://app.example.com"},
+		{"UV TTL", cfg.UVCacheMinutes, 90},
+		{"Location strategy", cfg.LocationCache.Strategy, "geohash"},
+		{"Tenant", cfg.Tenants[0].ID, "acme"},
+		{"Default max concurrency", cfg.MaxConcurrentReqs, 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.actual != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, tt.actual)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_FormatByExtension(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+		baseURL  string
+	}{
+		{"Flat JSON", ".apiConfig", `{"OpenWeatherMapApiKey":"json_key"}`, "https://api.openweathermap.org/data/2.5"},
+		{"JSON with base URL", "config.json", `{"OpenWeatherMapApiKey":"json_key","BaseURL":"http://mock:8080"}`, "http://mock:8080"},
+		{"YML extension", "config.yml", "weather_api:\n  api_key: yml_key\n", "https://api.openweathermap.org/data/2.5"},
+		{"Legacy weather endpoint", "config.yaml", "weather_api:\n  api_key: k\n  base_url: https://api.openweathermap.org/data/2.5/weather\n", "https://api.openweathermap.org/data/2.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := config.LoadConfig(writeConfig(t, tt.filename, tt.content))
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			if cfg.BaseURL != tt.baseURL {
+				t.Errorf("Expected base URL %s, got %s", tt.baseURL, cfg.BaseURL)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_RepositoryYAML(t *testing.T) {
+	t.Setenv("OWM_KEY", "env_key")
+	cfg, err := config.LoadConfig("../../configs/config.yaml")
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.ServerPort != "8080" || cfg.LogLevel != "info" || cfg.OpenWeatherMapApiKey != "env_key" {
+		t.Errorf("Unexpected config from configs/config.yaml: %+v", cfg)
+	}
+}
+
+func TestLoadConfig_EnvOverrides(t *testing.T) {
+	filename := writeConfig(t, ".apiConfig", `{"OpenWeatherMapApiKey":"file_key","CacheExpiryMinutes":10,"LogLevel":"info"}`)
+	t.Setenv("WEATHER_API_KEY", "env_key")
+	t.Setenv("CACHE_EXPIRY_MINUTES", "3")
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://a.example.com, https://b.example.com")
+	t.Setenv("AUTH_ENABLED", "false")
+	t.Setenv("LOCATION_CACHE_GRID_DEGREES", "0.05")
+	t.Setenv("TENANTS", `[{"ID":"acme","APIKeys":["acme-key"]}]`)
+
+	cfg, err := config.LoadConfig(filename)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	if cfg.OpenWeatherMapApiKey != "env_key" || cfg.CacheExpiryMinutes != 3 {
+		t.Errorf("Expected environment to override the file, got key=%s expiry=%d", cfg.OpenWeatherMapApiKey, cfg.CacheExpiryMinutes)
+	}
+	if cfg.LogLevel != "info" {
+		t.Errorf("Expected file value to be kept when no env var is set, got %s", cfg.LogLevel)
+	}
+	if len(cfg.CORS.AllowedOrigins) != 2 || cfg.CORS.AllowedOrigins[1] != "https://b.example.com" {
+		t.Errorf("Expected comma separated origins, got %v", cfg.CORS.AllowedOrigins)
+	}
+	if cfg.LocationCache.GridDegrees != 0.05 {
+		t.Errorf("Expected grid of 0.05, got %v", cfg.LocationCache.GridDegrees)
+	}
+	if len(cfg.Tenants) != 1 || cfg.Tenants[0].ID != "acme" {
+		t.Errorf("Expected tenant from TENANTS, got %+v", cfg.Tenants)
+	}
+	if cfg.RateLimitPerMinute != 100 {
+		t.Errorf("Expected default rate limit, got %d", cfg.RateLimitPerMinute)
+	}
+}
+
+func TestLoadConfig_EnvOnly(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), ".apiConfig")
+	if _, err := config.LoadConfig(missing); err == nil {
+		t.Fatal("Expected an error without a config file or WEATHER_API_KEY")
+	}
+
+	t.Setenv("OPENWEATHERMAP_API_KEY", "alias_key")
+	t.Setenv("PORT", "9999")
+	cfg, err := config.LoadConfig(missing)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.OpenWeatherMapApiKey != "alias_key" || cfg.ServerPort != "9999" {
+		t.Errorf("Expected alias variables to be honoured, got key=%s port=%s", cfg.OpenWeatherMapApiKey, cfg.ServerPort)
+	}
+}
+
+func TestLoadConfig_InvalidEnv(t *testing.T) {
+	t.Setenv("WEATHER_API_KEY", "env_key")
+	t.Setenv("RATE_LIMIT_PER_MINUTE", "lots")
+
+	_, err := config.LoadConfig(filepath.Join(t.TempDir(), ".apiConfig"))
+	if err == nil || !strings.Contains(err.Error(), "RATE_LIMIT_PER_MINUTE") {
+		t.Errorf("Expected error naming RATE_LIMIT_PER_MINUTE, got %v", err)
+	}
+}
+
+// TestEnvVars_CoverEveryField fails when a config field is added without an
+// environment variable
+func TestEnvVars_CoverEveryField(t *testing.T) {
+	var count func(reflect.Type) int
+	count = func(typ reflect.Type) int {
+		if typ.Kind() != reflect.Struct {
+			return 1
+		}
+		total := 0
+		for i := 0; i < typ.NumField(); i++ {
+			total += count(typ.Field(i).Type)
+		}
+		return total
+	}
+
+	if fields, vars := count(reflect.TypeOf(config.Config{})), len(config.EnvVars()); fields != vars {
+		t.Errorf("Config has %d fields but %d environment variables", fields, vars)
+	}
+}
+
+
+func TestSaveExampleConfig(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.json")
+	if err := config.SaveExampleConfig(filename); err != nil {
+		t.Fatalf("SaveExampleConfig() error = %v", err)
+	}
+
+	cfg, err := config.LoadConfig(filename)
+	if err != nil {
+		t.Fatalf("Expected example config to load, got %v", err)
+	}
+	if cfg.OpenWeatherMapApiKey != "your_api_key_here" || cfg.CacheExpiryMinutes != 10 {
+		t.Errorf("Unexpected example config: %+v", cfg)
+	}
+
+	if err := config.SaveExampleConfig(filepath.Join(t.TempDir(), "config.yaml")); err == nil {
+		t.Error("Expected YAML file name to be rejected")
+	}
+}
\ No newline at end of file
@@ -0,0 +1,128 @@
+package unit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/config"
+)
+
+func TestLoadConfig_ReportsEveryProblem(t *testing.T) {
+	filename := writeConfig(t, "config.json", `{
+		"OpenWeatherMapApiKey": "key",
+		"BaseURL": "not a url",
+		"CacheExpiryMinutes": -5,
+		"RateLimitPerMinute": -1,
+		"ServerPort": "abc",
+		"LogLevel": "verbose"
+	}`)
+
+	_, err := config.LoadConfig(filename)
+	var invalid *config.ValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("Expected *config.ValidationError, got %v", err)
+	}
+
+	expected := []string{"BaseURL", "CacheExpiryMinutes", "RateLimitPerMinute", "ServerPort", "LogLevel"}
+	if len(invalid.Problems) != len(expected) {
+		t.Errorf("Expected %d problems, got %d: %v", len(expected), len(invalid.Problems), invalid.Problems)
+	}
+	for _, field := range expected {
+		if !strings.Contains(err.Error(), field) {
+			t.Errorf("Expected error to mention %s, got %v", field, err)
+		}
+	}
+}
+
+func TestLoadConfig_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		problem string
+	}{
+		{"Port out of range", `{"OpenWeatherMapApiKey": "key", "ServerPort": "70000"}`, "ServerPort"},
+		{"Negative TTL", `{"OpenWeatherMapApiKey": "key", "UVCacheMinutes": -1}`, "UVCacheMinutes"},
+		{"TTL over a day", `{"OpenWeatherMapApiKey": "key", "NegativeCacheMinutes": 2000}`, "NegativeCacheMinutes"},
+		{"Negative TTL other than off", `{"OpenWeatherMapApiKey": "key", "NegativeCacheMinutes": -5}`, "NegativeCacheMinutes"},
+		{"Relative base URL", `{"OpenWeatherMapApiKey": "key", "BaseURL": "/data/2.5"}`, "BaseURL"},
+		{"Bad CORS origin", `{"OpenWeatherMapApiKey": "key", "CORS": {"AllowedOrigins": ["example.com"]}}`, "CORS.AllowedOrigins"},
+		{"Any origin with credentials", `{"OpenWeatherMapApiKey": "key", "CORS": {"AllowedOrigins": ["*"], "AllowCredentials": true}}`, "CORS.AllowCredentials"},
+		{"Bad JWKS URL", `{"OpenWeatherMapApiKey": "key", "Auth": {"Enabled": true, "JWKSURL": "ftp://keys"}}`, "Auth.JWKSURL"},
+		{"Auth without issuer", `{"OpenWeatherMapApiKey": "key", "Auth": {"Enabled": true, "KeyFile": "jwt.pem", "Audience": "weather-api"}}`, "Auth.Issuer"},
+		{"Auth without audience", `{"OpenWeatherMapApiKey": "key", "Auth": {"Enabled": true, "KeyFile": "jwt.pem", "Issuer": "https://idp.example.com"}}`, "Auth.Audience"},
+		{"Unknown backend", `{"OpenWeatherMapApiKey": "key", "CacheBackend": "disk"}`, "CacheBackend"},
+		{"Negative tenant limit", `{"OpenWeatherMapApiKey": "key", "Tenants": [{"ID": "acme", "RateLimitPerMinute": -1}]}`, "acme"},
+		{"Missing API key", `{"CacheExpiryMinutes": 5}`, "OpenWeatherMapApiKey"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := config.LoadConfig(writeConfig(t, "config.json", tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.problem) {
+				t.Errorf("Expected error mentioning %s, got %v", tt.problem, err)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_AcceptsValidEdgeCases(t *testing.T) {
+	filename := writeConfig(t, "config.json", `{
+		"OpenWeatherMapApiKey": "key",
+		"ServerPort": "65535",
+		"LogLevel": "WARN",
+		"NegativeCacheMinutes": -1,
+		"CORS": {"AllowedOrigins": ["*", "https://*.example.com"]}
+	}`)
+
+	if _, err := config.LoadConfig(filename); err != nil {
+		t.Errorf("Expected config to be valid, got %v", err)
+	}
+}
+
+func TestLoadConfig_RejectsTrailingData(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{"JSON garbage", "config.json", `{"OpenWeatherMapApiKey": "key"} garbage`},
+		{"Second JSON object", "config.json", `{"OpenWeatherMapApiKey": "key"}` + "\n" + `{"ServerPort": "9090"}`},
+		{"Second YAML document", "config.yaml", "weather_api:\n  api_key: key\n---\nserver:\n  port: 9090\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := config.LoadConfig(writeConfig(t, tt.file, tt.content)); err == nil || !strings.Contains(err.Error(), "unexpected") {
+				t.Errorf("Expected trailing data to be rejected, got %v", err)
+			}
+		})
+	}
+
+	if _, err := config.LoadConfig(writeConfig(t, "config.json", `{"OpenWeatherMapApiKey": "key"}`+"\n\n")); err != nil {
+		t.Errorf("Expected trailing whitespace to be accepted, got %v", err)
+	}
+}
+
+func TestLoadConfig_RejectsUnknownKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		key     string
+	}{
+		{"JSON", "config.json", `{"OpenWeatherMapApiKey": "key", "CacheExpiryMinuts": 5}`, "CacheExpiryMinuts"},
+		{"JSON nested", "config.json", `{"OpenWeatherMapApiKey": "key", "Redis": {"Address": "redis:6379"}}`, "Address"},
+		{"YAML", "config.yaml", "weather_api:\n  api_key: key\n  cache_expiry: 5\n", "cache_expiry"},
+		{"YAML section", "config.yaml", "weather_api:\n  api_key: key\nlogging:\n  level: debug\n", "logging"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := config.LoadConfig(writeConfig(t, tt.file, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.key) {
+				t.Errorf("Expected unknown key %s to be rejected, got %v", tt.key, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/middleware"
+	"github.com/gorilla/mux"
+)
+
+func newCORSTestRouter() *mux.Router {
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	router := mux.NewRouter()
+	router.HandleFunc("/weather", ok).Methods("GET")
+	router.HandleFunc("/cache/clear", ok).Methods("POST")
+	return router
+}
+
+func TestRouteMethods(t *testing.T) {
+	got := middleware.RouteMethods(newCORSTestRouter())
+	want := []string{"GET", "OPTIONS", "POST"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected methods %v, got %v", want, got)
+	}
+}
+
+func TestCORSPolicy(t *testing.T) {
+	router := newCORSTestRouter()
+	policy := middleware.NewCORSPolicy(middleware.CORSOptions{
+		AllowedOrigins:   []string{"https://app.example.com", "https://*.internal.example.com"},
+		AllowedMethods:   middleware.RouteMethods(router),
+		AllowedHeaders:   []string{"Content-Type", "Authorization"},
+		AllowCredentials: true,
+		MaxAgeSeconds:    600,
+	})
+	handler := policy.Middleware(router)
+
+	tests := []struct {
+		name          string
+		method        string
+		origin        string
+		requestMethod string
+		requestHeader string
+		wantStatus    int
+		wantOrigin    string
+	}{
+		{"Exact origin", "GET", "https://app.example.com", "", "", http.StatusOK, "https://app.example.com"},
+		{"Wildcard subdomain", "GET", "https://team.internal.example.com", "", "", http.StatusOK, "https://team.internal.example.com"},
+		{"Wildcard does not match bare domain", "GET", "https://internal.example.com", "", "", http.StatusOK, ""},
+		{"Wildcard requires scheme", "GET", "http://team.internal.example.com", "", "", http.StatusOK, ""},
+		{"Disallowed origin", "GET", "https://evil.example.org", "", "", http.StatusOK, ""},
+		{"Preflight allowed", "OPTIONS", "https://app.example.com", "POST", "content-type", http.StatusNoContent, "https://app.example.com"},
+		{"Preflight unsupported method", "OPTIONS", "https://app.example.com", "DELETE", "", http.StatusForbidden, ""},
+		{"Preflight disallowed header", "OPTIONS", "https://app.example.com", "GET", "X-Custom", http.StatusForbidden, ""},
+		{"Preflight disallowed origin", "OPTIONS", "https://evil.example.org", "GET", "", http.StatusForbidden, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/weather", nil)
+			req.Header.Set("Origin", tt.origin)
+			if tt.requestMethod != "" {
+				req.Header.Set("Access-Control-Request-Method", tt.requestMethod)
+			}
+			if tt.requestHeader != "" {
+				req.Header.Set("Access-Control-Request-Headers", tt.requestHeader)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("Expected Access-Control-Allow-Origin %q, got %q", tt.wantOrigin, got)
+			}
+			if tt.wantOrigin == "" && rec.Header().Get("Access-Control-Allow-Credentials") != "" {
+				t.Error("Expected no CORS headers for rejected request")
+			}
+		})
+	}
+}
+
+func TestCORSPolicy_PreflightHeaders(t *testing.T) {
+	router := newCORSTestRouter()
+	policy := middleware.NewCORSPolicy(middleware.CORSOptions{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: middleware.RouteMethods(router),
+		MaxAgeSeconds:  600,
+	})
+
+	req := httptest.NewRequest("OPTIONS", "/cache/clear", nil)
+	req.Header.Set("Origin", "https://anywhere.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	rec := httptest.NewRecorder()
+	policy.Middleware(router).ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Expected wildcard origin, got %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Methods"); got != "GET, OPTIONS, POST" {
+		t.Errorf("Expected methods from routes, got %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Max-Age"); got != "600" {
+		t.Errorf("Expected max age 600, got %q", got)
+	}
+}
+
+func TestCORSPolicy_AnyOriginNeverGetsCredentials(t *testing.T) {
+	policy := middleware.NewCORSPolicy(middleware.CORSOptions{
+		AllowedOrigins:   []string{"*", "https://app.example.com"},
+		AllowedMethods:   []string{"GET", "OPTIONS"},
+		AllowCredentials: true,
+	})
+	handler := policy.Middleware(newCORSTestRouter())
+
+	req := httptest.NewRequest("GET", "/weather", nil)
+	req.Header.Set("Origin", "https://evil.example.org")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "*" || rec.Header().Get("Access-Control-Allow-Credentials") != "" {
+		t.Errorf("Expected '*' without credentials for an unlisted origin, got %q", got)
+	}
+
+	req.Header.Set("Origin", "https://app.example.com")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" || rec.Header().Get("Access-Control-Allow-Credentials") != "true" {
+		t.Error("Expected a listed origin to keep credentialed access")
+	}
+}
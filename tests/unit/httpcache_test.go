@@ -0,0 +1,107 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/api/openweathermap"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/cache"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/handlers"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/metrics"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/models"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/services"
+)
+
+// newCachedWeatherHandler returns a handler whose cache already holds London
+func newCachedWeatherHandler(t *testing.T) *handlers.Handler {
+	t.Helper()
+	cacheManager := cache.NewCacheManager(10 * time.Minute)
+	metricsManager := metrics.NewMetricsManager()
+	service := services.NewWeatherService(openweathermap.NewClient("test_api_key"), cacheManager, metricsManager)
+
+	cacheManager.Set("london", models.WeatherData{
+		Name:    "London",
+		Country: "GB",
+		Dt:      time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC).Unix(),
+	})
+	return handlers.NewHandler(service, metricsManager, cacheManager)
+}
+
+func getWeather(h *handlers.Handler, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/weather?city=London", nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	h.WeatherHandler(rec, req)
+	return rec
+}
+
+func TestWeatherHandler_CacheHeaders(t *testing.T) {
+	h := newCachedWeatherHandler(t)
+	rec := getWeather(h, nil)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+	if etag := rec.Header().Get("ETag"); !strings.HasPrefix(etag, `W/"`) {
+		t.Errorf("Expected weak ETag, got %q", etag)
+	}
+	if got := rec.Header().Get("Last-Modified"); got != "Wed, 01 May 2024 12:00:00 GMT" {
+		t.Errorf("Expected Last-Modified from upstream dt, got %q", got)
+	}
+	cacheControl := rec.Header().Get("Cache-Control")
+	if !strings.HasPrefix(cacheControl, "public, max-age=") || cacheControl == "public, max-age=0" {
+		t.Errorf("Expected max-age from remaining TTL, got %q", cacheControl)
+	}
+
+	// The ETag must be stable across cache hits
+	if again := getWeather(h, nil); again.Header().Get("ETag") != rec.Header().Get("ETag") {
+		t.Error("Expected identical ETag for unchanged cache entry")
+	}
+}
+
+func TestWeatherHandler_ConditionalRequests(t *testing.T) {
+	h := newCachedWeatherHandler(t)
+	etag := getWeather(h, nil).Header().Get("ETag")
+
+	tests := []struct {
+		name       string
+		headers    map[string]string
+		wantStatus int
+	}{
+		{"Matching ETag", map[string]string{"If-None-Match": etag}, http.StatusNotModified},
+		{"Matching ETag in list", map[string]string{"If-None-Match": `"other", ` + etag}, http.StatusNotModified},
+		{"Stale ETag", map[string]string{"If-None-Match": `W/"stale"`}, http.StatusOK},
+		{"Not modified since", map[string]string{"If-Modified-Since": "Wed, 01 May 2024 12:00:00 GMT"}, http.StatusNotModified},
+		{"Modified since", map[string]string{"If-Modified-Since": "Wed, 01 May 2024 11:00:00 GMT"}, http.StatusOK},
+		{"If-None-Match takes precedence", map[string]string{
+			"If-None-Match":     `W/"stale"`,
+			"If-Modified-Since": "Wed, 01 May 2024 12:00:00 GMT",
+		}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := getWeather(h, tt.headers)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if rec.Code == http.StatusNotModified && rec.Body.Len() != 0 {
+				t.Error("Expected empty body for 304 response")
+			}
+		})
+	}
+}
+
+func TestWeatherHandler_PrivateCacheControl(t *testing.T) {
+	h := newCachedWeatherHandler(t)
+	rec := getWeather(h, map[string]string{"X-API-Key": "key-a"})
+
+	if got := rec.Header().Get("Cache-Control"); !strings.HasPrefix(got, "private") {
+		t.Errorf("Expected private Cache-Control for credentialed request, got %q", got)
+	}
+}
@@ -0,0 +1,168 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/api/openweathermap"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/cache"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/handlers"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/location"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/metrics"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/services"
+)
+
+func TestCanonicalCity(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Trim and lower", "  London ", "london"},
+		{"Collapse whitespace", "New \t  York", "new york"},
+		{"Full-width letters", "ＬＯＮＤＯＮ", "london"},
+		{"Composed accent", "São Paulo", "são paulo"},
+		{"Decomposed accent", "Sa\u0303o Paulo", "são paulo"},
+		{"Only whitespace", " \t ", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := location.CanonicalCity(tt.input); result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestLocationKeyer(t *testing.T) {
+	tests := []struct {
+		name      string
+		strategy  string
+		a, b      [2]float64
+		sameEntry bool
+	}{
+		{"Grid nearby", location.StrategyGrid, [2]float64{51.5085, -0.1257}, [2]float64{51.5121, -0.1281}, true},
+		{"Grid far apart", location.StrategyGrid, [2]float64{51.5085, -0.1257}, [2]float64{48.8566, 2.3522}, false},
+		{"Geohash nearby", location.StrategyGeohash, [2]float64{51.5085, -0.1257}, [2]float64{51.5087, -0.1255}, true},
+		{"Geohash far apart", location.StrategyGeohash, [2]float64{51.5085, -0.1257}, [2]float64{48.8566, 2.3522}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyer, err := location.NewKeyer(tt.strategy, location.DefaultGridDegrees, location.DefaultGeohashPrecision)
+			if err != nil {
+				t.Fatalf("NewKeyer() error = %v", err)
+			}
+			keyA, keyB := keyer.Key(tt.a[0], tt.a[1]), keyer.Key(tt.b[0], tt.b[1])
+			if (keyA == keyB) != tt.sameEntry {
+				t.Errorf("Expected same entry = %v, got keys %s and %s", tt.sameEntry, keyA, keyB)
+			}
+		})
+	}
+}
+
+func TestLocationKeyer_Geohash(t *testing.T) {
+	keyer, _ := location.NewKeyer(location.StrategyGeohash, 0, 11)
+	if key := keyer.Key(57.64911, 10.40744); key != "geohash:u4pruydqqvj" {
+		t.Errorf("Expected geohash:u4pruydqqvj, got %s", key)
+	}
+
+	lat, lon := keyer.Snap(57.64911, 10.40744)
+	if lat < 57.6491 || lat > 57.6492 || lon < 10.4074 || lon > 10.4075 {
+		t.Errorf("Expected snapped point inside the cell, got %v,%v", lat, lon)
+	}
+}
+
+func TestLocationKeyer_GridEdges(t *testing.T) {
+	keyer, err := location.NewKeyer(location.StrategyGrid, 7, 0)
+	if err != nil {
+		t.Fatalf("NewKeyer() error = %v", err)
+	}
+
+	// 7 does not divide 90 or 180, so the nearest grid points lie off the globe
+	for _, point := range [][2]float64{{90, 180}, {-90, -180}, {89.9, 179.9}} {
+		lat, lon := keyer.Snap(point[0], point[1])
+		if !location.ValidCoordinates(lat, lon) {
+			t.Errorf("Expected %v to snap onto the globe, got %v,%v", point, lat, lon)
+		}
+		key := keyer.Key(point[0], point[1])
+		parsedLat, parsedLon, ok := location.ParseKey(key)
+		if !ok || parsedLat != lat || parsedLon != lon {
+			t.Errorf("Expected key %s to parse back to %v,%v, got %v,%v (ok=%v)", key, lat, lon, parsedLat, parsedLon, ok)
+		}
+	}
+}
+
+func TestLocationKeyer_Invalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		strategy  string
+		grid      float64
+		precision int
+	}{
+		{"Unknown strategy", "h3", 0.01, 6},
+		{"Zero grid", location.StrategyGrid, 0, 6},
+		{"Geohash too precise", location.StrategyGeohash, 0.01, 13},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := location.NewKeyer(tt.strategy, tt.grid, tt.precision); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestWeatherService_NearbyCoordinatesShareEntry(t *testing.T) {
+	upstream := newFakeUpstream(t)
+	cacheManager := cache.NewCacheManager(10 * time.Minute)
+	service := services.NewWeatherService(openweathermap.NewClientWithBaseURL("test_api_key", upstream.URL), cacheManager, metrics.NewMetricsManager())
+
+	if _, err := service.GetWeatherDataByCoordsForTenant(nil, 51.5085, -0.1257, openweathermap.Options{}); err != nil {
+		t.Fatalf("GetWeatherDataByCoordsForTenant() error = %v", err)
+	}
+	data, err := service.GetWeatherDataByCoordsForTenant(nil, 51.5121, -0.1281, openweathermap.Options{})
+	if err != nil {
+		t.Fatalf("GetWeatherDataByCoordsForTenant() error = %v", err)
+	}
+
+	if !data.CacheHit {
+		t.Error("Expected nearby coordinates to hit the shared entry")
+	}
+	if calls := upstream.Calls("weather"); calls != 1 {
+		t.Errorf("Expected 1 upstream weather call, got %d", calls)
+	}
+}
+
+func TestWeatherHandler_Coordinates(t *testing.T) {
+	upstream := newFakeUpstream(t)
+	cacheManager := cache.NewCacheManager(10 * time.Minute)
+	metricsManager := metrics.NewMetricsManager()
+	service := services.NewWeatherService(openweathermap.NewClientWithBaseURL("test_api_key", upstream.URL), cacheManager, metricsManager)
+	handler := handlers.NewHandler(service, metricsManager, cacheManager)
+
+	tests := []struct {
+		name         string
+		url          string
+		expectedCode int
+	}{
+		{"Valid coordinates", "/weather?lat=51.5085&lon=-0.1257", http.StatusOK},
+		{"Missing lon", "/weather?lat=51.5085", http.StatusBadRequest},
+		{"Not a number", "/weather?lat=north&lon=-0.1257", http.StatusBadRequest},
+		{"Out of range", "/weather?lat=91&lon=0", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.WeatherHandler(rec, httptest.NewRequest(http.MethodGet, tt.url, nil))
+			if rec.Code != tt.expectedCode {
+				t.Errorf("Expected status %d, got %d", tt.expectedCode, rec.Code)
+			}
+		})
+	}
+}
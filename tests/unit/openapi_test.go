@@ -0,0 +1,221 @@
+package unit
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/handlers"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/models"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/openapi"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/server"
+	"github.com/gorilla/mux"
+)
+
+// routerOperations lists every "METHOD path" registered on the real router
+func routerOperations(t *testing.T) []string {
+	t.Helper()
+	router := server.NewRouter(server.Options{Handler: handlers.NewHandler(nil, nil, nil)})
+
+	var operations []string
+	router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		path, pathErr := route.GetPathTemplate()
+		methods, methodsErr := route.GetMethods()
+		if pathErr != nil || methodsErr != nil {
+			return nil
+		}
+		for _, method := range methods {
+			operations = append(operations, method+" "+path)
+		}
+		return nil
+	})
+	sort.Strings(operations)
+	return operations
+}
+
+func TestOpenAPI_MatchesRoutes(t *testing.T) {
+	doc := server.OpenAPI()
+
+	var current []string
+	for _, operation := range doc.Operations() {
+		method, path, _ := strings.Cut(operation, " ")
+		if doc.Paths[path][strings.ToLower(method)].Deprecated {
+			continue
+		}
+		current = append(current, operation)
+	}
+
+	routes := routerOperations(t)
+	if !reflect.DeepEqual(routes, current) {
+		t.Errorf("Routes and OpenAPI document have drifted apart\nroutes: %v\nspec:   %v", routes, current)
+	}
+}
+
+func TestOpenAPI_LegacyAliasesDocumented(t *testing.T) {
+	doc := server.OpenAPI()
+
+	for path, item := range doc.Paths {
+		if !strings.HasPrefix(path, "/"+server.DefaultVersion+"/") {
+			continue
+		}
+		legacyPath := strings.TrimPrefix(path, "/"+server.DefaultVersion)
+		for method := range item {
+			legacy, found := doc.Paths[legacyPath][method]
+			if !found || !legacy.Deprecated {
+				t.Errorf("Expected deprecated alias %s %s", strings.ToUpper(method), legacyPath)
+			}
+		}
+	}
+}
+
+func TestOpenAPI_PathParameters(t *testing.T) {
+	doc := server.OpenAPI()
+	placeholder := regexp.MustCompile(`\{([^}]+)\}`)
+
+	for path, item := range doc.Paths {
+		for method, operation := range item {
+			for _, match := range placeholder.FindAllStringSubmatch(path, -1) {
+				documented := false
+				for _, param := range operation.Parameters {
+					if param.In == "path" && param.Name == match[1] && param.Required {
+						documented = true
+					}
+				}
+				if !documented {
+					t.Errorf("%s %s does not document required path parameter %s", strings.ToUpper(method), path, match[1])
+				}
+			}
+			if len(operation.Responses) == 0 {
+				t.Errorf("%s %s documents no responses", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
+
+// jsonPaths lists the dotted JSON path of every field of typ
+func jsonPaths(typ reflect.Type, prefix string) []string {
+	for typ.Kind() == reflect.Slice {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct || typ == reflect.TypeOf(time.Time{}) {
+		return nil
+	}
+	var paths []string
+	for _, field := range openapi.JSONFields(typ) {
+		path := prefix + field.Name
+		paths = append(paths, path)
+		paths = append(paths, jsonPaths(field.Type, path+".")...)
+	}
+	return paths
+}
+
+func TestOpenAPI_WeatherDataFields(t *testing.T) {
+	paths := jsonPaths(reflect.TypeOf(models.WeatherData{}), "")
+	for _, path := range paths {
+		if server.WeatherDataDescriptions[path] == "" {
+			t.Errorf("WeatherData field %s is not documented", path)
+		}
+	}
+	if len(server.WeatherDataDescriptions) != len(paths) {
+		t.Errorf("Expected %d WeatherData descriptions, got %d; remove descriptions of deleted fields", len(paths), len(server.WeatherDataDescriptions))
+	}
+
+	schema := server.OpenAPI().Components.Schemas["WeatherData"]
+	if schema.Properties["main"].Properties["temp_celsius"].Description == "" {
+		t.Error("Expected nested WeatherData fields to carry descriptions")
+	}
+}
+
+func TestOpenAPI_SingleUnitWeatherDataFields(t *testing.T) {
+	paths := make(map[string]bool)
+	for _, path := range jsonPaths(reflect.TypeOf(models.SingleUnitWeatherData{}), "") {
+		paths[path] = true
+		if server.SingleUnitDescriptions[path] == "" && server.WeatherDataDescriptions[path] == "" {
+			t.Errorf("SingleUnitWeatherData field %s is not documented", path)
+		}
+	}
+	for path := range server.SingleUnitDescriptions {
+		if !paths[path] {
+			t.Errorf("SingleUnitDescriptions documents missing field %s", path)
+		}
+	}
+
+	schema := server.OpenAPI().Components.Schemas["SingleUnitWeatherData"]
+	if _, found := schema.Properties["main"].Properties["temp"]; !found || schema.Properties["uv_index"] == nil {
+		t.Error("Expected SingleUnitWeatherData to replace main and keep the other WeatherData fields")
+	}
+	if _, found := schema.Properties["main"].Properties["temp_celsius"]; found {
+		t.Error("Expected the replaced WeatherData main object to be hidden")
+	}
+}
+
+func TestOpenAPI_ReferencesResolve(t *testing.T) {
+	doc := server.OpenAPI()
+	body, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Failed to encode document: %v", err)
+	}
+
+	for _, match := range regexp.MustCompile(`"\$ref":"#/components/schemas/([^"]+)"`).FindAllStringSubmatch(string(body), -1) {
+		if _, found := doc.Components.Schemas[match[1]]; !found {
+			t.Errorf("Reference to undefined schema %s", match[1])
+		}
+	}
+}
+
+func TestOpenAPI_Served(t *testing.T) {
+	api := newAPIServer(t, nil)
+
+	resp, err := http.Get(api.URL + "/openapi.json")
+	if err != nil {
+		t.Fatalf("GET /openapi.json error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	var doc openapi.Document
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		t.Fatalf("Failed to decode OpenAPI document: %v", err)
+	}
+	if doc.OpenAPI != openapi.Version || len(doc.Paths) == 0 {
+		t.Errorf("Unexpected document: openapi=%q, %d paths", doc.OpenAPI, len(doc.Paths))
+	}
+
+	resp, err = http.Get(api.URL + "/")
+	if err != nil {
+		t.Fatalf("GET / error = %v", err)
+	}
+	defer resp.Body.Close()
+	page, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(page), `<script src="/docs/docs.js">`) || strings.Contains(string(page), "https://") {
+		t.Errorf("Expected the documentation page to load its script from this origin only, got %s", page)
+	}
+	csp := resp.Header.Get("Content-Security-Policy")
+	if !strings.HasPrefix(csp, "default-src 'none'") || !strings.Contains(csp, "script-src 'self'") || !strings.Contains(csp, "connect-src 'self'") {
+		t.Errorf("Expected a same-origin Content-Security-Policy, got %q", csp)
+	}
+
+	// The script renders /openapi.json
+	for asset, content := range map[string]string{"/docs/docs.js": `fetch("/openapi.json")`, "/docs/docs.css": ".operation"} {
+		resp, err := http.Get(api.URL + asset)
+		if err != nil {
+			t.Fatalf("GET %s error = %v", asset, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), content) {
+			t.Errorf("Expected %s to be served, got %d", asset, resp.StatusCode)
+		}
+	}
+	if resp, err := http.Get(api.URL + "/docs/missing.js"); err == nil {
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("Expected 404 for an unknown asset, got %d", resp.StatusCode)
+		}
+	}
+}
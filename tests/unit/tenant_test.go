@@ -0,0 +1,111 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/metrics"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/middleware"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/tenant"
+)
+
+func newTestRegistry() *tenant.Registry {
+	return tenant.NewRegistry([]*tenant.Tenant{
+		{
+			ID:                 "team-a",
+			Credentials:        []string{"key-a"},
+			RateLimitPerMinute: 2,
+			CacheNamespace:     "a",
+			AllowedEndpoints:   []string{"/weather"},
+		},
+		{
+			ID:             "team-b",
+			Credentials:    []string{"key-b"},
+			CacheNamespace: "b",
+		},
+	}, &tenant.Tenant{ID: tenant.DefaultID})
+}
+
+func TestTenantMiddleware(t *testing.T) {
+	var resolved *tenant.Tenant
+	handler := middleware.TenantMiddleware(newTestRegistry())(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			resolved, _ = tenant.FromContext(r.Context())
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+
+	tests := []struct {
+		name       string
+		path       string
+		apiKey     string
+		wantStatus int
+		wantTenant string
+	}{
+		{"Missing credentials", "/weather", "", http.StatusUnauthorized, ""},
+		{"Unknown credentials", "/weather", "nope", http.StatusUnauthorized, ""},
+		{"Tenant A weather", "/weather", "key-a", http.StatusOK, "team-a"},
+		{"Tenant A endpoint not allowed", "/cache", "key-a", http.StatusForbidden, ""},
+		{"Tenant B any endpoint", "/cache", "key-b", http.StatusOK, "team-b"},
+		{"Tenant A second request", "/weather", "key-a", http.StatusOK, "team-a"},
+		{"Tenant A rate limited", "/weather", "key-a", http.StatusTooManyRequests, ""},
+		{"Tenant B unaffected by A's limit", "/weather", "key-b", http.StatusOK, "team-b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved = nil
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if tt.wantTenant != "" && (resolved == nil || resolved.ID != tt.wantTenant) {
+				t.Errorf("Expected tenant %s, got %v", tt.wantTenant, resolved)
+			}
+		})
+	}
+}
+
+func TestTenantRegistry_DefaultTenant(t *testing.T) {
+	registry := tenant.NewRegistry(nil, &tenant.Tenant{ID: tenant.DefaultID})
+
+	resolved, found := registry.Resolve(httptest.NewRequest(http.MethodGet, "/weather", nil), "")
+	if !found || resolved.ID != tenant.DefaultID {
+		t.Errorf("Expected default tenant when no tenants are configured, got %v", resolved)
+	}
+	if key := resolved.CacheKey("london"); key != "london" {
+		t.Errorf("Expected default tenant cache key to be unscoped, got %s", key)
+	}
+}
+
+func TestTenantCacheKey(t *testing.T) {
+	registry := newTestRegistry()
+	a, _ := registry.Get("team-a")
+	b, _ := registry.Get("team-b")
+
+	if a.CacheKey("london") == b.CacheKey("london") {
+		t.Error("Expected tenants to use separate cache namespaces")
+	}
+}
+
+func TestMetricsManager_TenantUsage(t *testing.T) {
+	m := metrics.NewMetricsManager()
+	m.RecordTenantRequest("team-a", false, 3, nil)
+	m.RecordTenantRequest("team-a", true, 0, nil)
+	m.RecordTenantRequest("team-b", false, 1, http.ErrHandlerTimeout)
+
+	usage := m.GetTenantUsage()
+	if usage["team-a"].Requests != 2 || usage["team-a"].CacheHits != 1 || usage["team-a"].UpstreamCalls != 3 {
+		t.Errorf("Unexpected usage for team-a: %+v", usage["team-a"])
+	}
+	if usage["team-b"].Errors != 1 {
+		t.Errorf("Expected 1 error for team-b, got %+v", usage["team-b"])
+	}
+}
@@ -0,0 +1,210 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/api/openweathermap"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/cache"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/metrics"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/services"
+	"github.com/Vivek-Prakash1307/weather-Microservices/pkg/client"
+)
+
+func TestParseUnits(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    openweathermap.Units
+		wantErr bool
+	}{
+		{"", openweathermap.Metric, false},
+		{"metric", openweathermap.Metric, false},
+		{"Imperial", openweathermap.Imperial, false},
+		{"standard", openweathermap.Standard, false},
+		{"kelvin", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := openweathermap.ParseUnits(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseUnits(%q) = %q, %v; expected %q, error %v", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseLang(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"en", "", false},
+		{"DE", "de", false},
+		{"pt-BR", "pt_br", false},
+		{"cs", "cz", false},
+		{"se", "sv", false},
+		{"xx", "", true},
+		{"de&appid=x", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := openweathermap.ParseLang(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseLang(%q) = %q, %v; expected %q, error %v", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestWeatherService_UnitsAndLang(t *testing.T) {
+	upstream := newFakeUpstream(t)
+	cacheManager := cache.NewCacheManager(10 * time.Minute)
+	service := services.NewWeatherService(openweathermap.NewClientWithBaseURL("test_api_key", upstream.URL), cacheManager, metrics.NewMetricsManager())
+	imperialGerman := openweathermap.Options{Units: openweathermap.Imperial, Lang: "de"}
+
+	data, err := service.GetWeatherDataForTenant(nil, "London", imperialGerman)
+	if err != nil {
+		t.Fatalf("GetWeatherDataForTenant() error = %v", err)
+	}
+	if query := upstream.Query("weather"); query.Get("units") != "imperial" || query.Get("lang") != "de" {
+		t.Errorf("Expected units and lang to be passed upstream, got %v", query)
+	}
+	if data.Main.Fahrenheit != 59 || math.Abs(data.Main.Celsius-15) > 1e-9 || math.Abs(data.Main.Kelvin-288.15) > 1e-9 {
+		t.Errorf("Expected 59°F converted to every scale, got %+v", data.Main)
+	}
+	if data.Wind.SpeedMph != 11.18 || math.Abs(data.Wind.Speed-5) > 0.01 {
+		t.Errorf("Expected 11.18 mph converted to m/s, got %+v", data.Wind)
+	}
+	if len(data.Weather) != 1 || data.Weather[0].Description != "Bedeckt" {
+		t.Errorf("Expected a German description, got %+v", data.Weather)
+	}
+
+	// The same options are served from cache; other options are fetched again
+	service.GetWeatherDataForTenant(nil, "London", imperialGerman)
+	if calls := upstream.Calls("weather"); calls != 1 {
+		t.Errorf("Expected 1 upstream call for repeated options, got %d", calls)
+	}
+	data, _ = service.GetWeatherData("London")
+	if calls := upstream.Calls("weather"); calls != 2 || data.Weather[0].Description != "overcast clouds" {
+		t.Errorf("Expected a separate metric English lookup, got %d calls and %+v", calls, data.Weather)
+	}
+	if query := upstream.Query("weather"); query.Get("units") != "metric" || query.Has("lang") {
+		t.Errorf("Expected metric units without lang by default, got %v", query)
+	}
+
+	keys, _ := cacheManager.Keys()
+	want := map[string]bool{"london": true, "london;units=imperial;lang=de": true}
+	for _, key := range keys {
+		delete(want, key)
+	}
+	if len(want) != 0 {
+		t.Errorf("Expected cache keys %v, got %v", want, keys)
+	}
+}
+
+func TestWeatherService_NotFoundSharedAcrossOptions(t *testing.T) {
+	upstream := newFakeUpstream(t)
+	service := services.NewWeatherService(openweathermap.NewClientWithBaseURL("test_api_key", upstream.URL), cache.NewCacheManager(10*time.Minute), metrics.NewMetricsManager())
+
+	service.GetWeatherData("lndon")
+	_, err := service.GetWeatherDataForTenant(nil, "lndon", openweathermap.Options{Units: openweathermap.Imperial})
+	var notFound *openweathermap.NotFoundError
+	if !errors.As(err, &notFound) || upstream.Calls("weather") != 1 {
+		t.Errorf("Expected the negative entry to cover every unit system, got %v after %d calls", err, upstream.Calls("weather"))
+	}
+
+	_, err = service.GetWeatherData("london;units=imperial")
+	if !errors.As(err, &notFound) || upstream.Calls("weather") != 1 {
+		t.Errorf("Expected a city containing ';' to be rejected without a lookup, got %v", err)
+	}
+}
+
+func TestWeatherService_RefreshOptionsKey(t *testing.T) {
+	upstream := newFakeUpstream(t)
+	service := services.NewWeatherService(openweathermap.NewClientWithBaseURL("test_api_key", upstream.URL), cache.NewCacheManager(10*time.Minute), metrics.NewMetricsManager())
+
+	data, err := service.RefreshCacheEntry(nil, "london;units=standard;lang=de")
+	if err != nil {
+		t.Fatalf("RefreshCacheEntry() error = %v", err)
+	}
+	if data.Main.Kelvin != 288.15 || data.Weather[0].Description != "Bedeckt" {
+		t.Errorf("Expected the key's options to be used, got %+v", data)
+	}
+
+	for _, key := range []string{"london;units=bogus", "london;units=metric", "london;lang=de;units=imperial", "london;colour=red"} {
+		if _, err := service.RefreshCacheEntry(nil, key); !errors.Is(err, services.ErrInvalidCacheKey) {
+			t.Errorf("RefreshCacheEntry(%q) error = %v, expected ErrInvalidCacheKey", key, err)
+		}
+	}
+}
+
+func TestWeatherHandler_SingleUnit(t *testing.T) {
+	api := newAPIServer(t, nil)
+
+	resp, err := http.Get(api.URL + "/v1/weather?city=London&units=imperial&single_unit=true")
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	var body map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&body)
+	main, _ := body["main"].(map[string]interface{})
+	wind, _ := body["wind"].(map[string]interface{})
+	if body["units"] != "imperial" || main["temp"] != 59.0 || wind["speed"] != 11.18 {
+		t.Errorf("Expected imperial values only, got %v", body)
+	}
+	if _, found := main["temp_celsius"]; found {
+		t.Error("Expected other unit systems to be left out")
+	}
+	if body["name"] != "London" || body["uv_index"] != 4.5 {
+		t.Errorf("Expected the remaining fields to be kept, got %v", body)
+	}
+
+	full, err := http.Get(api.URL + "/v1/weather?city=London&units=imperial")
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	full.Body.Close()
+	if full.Header.Get("ETag") == resp.Header.Get("ETag") {
+		t.Error("Expected single unit and full responses to have different ETags")
+	}
+}
+
+func TestWeatherHandler_InvalidUnitsAndLang(t *testing.T) {
+	api := newAPIServer(t, nil)
+
+	for _, query := range []string{"units=kelvin", "lang=xx", "single_unit=maybe"} {
+		resp, err := http.Get(api.URL + "/v1/weather?city=London&" + query)
+		if err != nil {
+			t.Fatalf("GET error = %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %s, got %d", query, resp.StatusCode)
+		}
+	}
+}
+
+func TestClient_WeatherOptions(t *testing.T) {
+	c := newTestClient(t, newAPIServer(t, nil).URL)
+	ctx := context.Background()
+
+	data, err := c.Weather(ctx, "London", client.InUnits("imperial"), client.InLanguage("de"))
+	if err != nil {
+		t.Fatalf("Weather() error = %v", err)
+	}
+	if data.Main.Fahrenheit != 59 || data.Weather[0].Description != "Bedeckt" {
+		t.Errorf("Expected imperial German weather, got %+v", data)
+	}
+
+	refreshed, err := c.RefreshCacheEntry(ctx, "london;units=imperial;lang=de")
+	if err != nil || refreshed.Weather[0].Description != "Bedeckt" {
+		t.Errorf("Expected the options key to be refreshed, got %+v (err=%v)", refreshed, err)
+	}
+}
@@ -0,0 +1,89 @@
+package unit
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeUpstream is an OpenWeatherMap stand-in that counts calls per endpoint.
+// Weather is 15°C with 5 m/s wind, reported in the requested units, and
+// described in German for lang=de.
+type fakeUpstream struct {
+	*httptest.Server
+	mu      sync.Mutex
+	calls   map[string]int
+	queries map[string]url.Values
+	failing map[string]bool
+}
+
+func newFakeUpstream(t *testing.T) *fakeUpstream {
+	t.Helper()
+	f := &fakeUpstream{calls: make(map[string]int), queries: make(map[string]url.Values), failing: make(map[string]bool)}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		endpoint := strings.TrimPrefix(r.URL.Path, "/")
+		f.mu.Lock()
+		f.calls[endpoint]++
+		f.queries[endpoint] = r.URL.Query()
+		failing := f.failing[endpoint]
+		f.mu.Unlock()
+		if failing {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch endpoint {
+		case "weather":
+			if strings.EqualFold(r.URL.Query().Get("q"), "lndon") {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			temp, wind := 15.0, 5.0
+			switch r.URL.Query().Get("units") {
+			case "imperial":
+				temp, wind = 59, 11.18
+			case "standard":
+				temp = 288.15
+			}
+			description := "overcast clouds"
+			if r.URL.Query().Get("lang") == "de" {
+				description = "Bedeckt"
+			}
+			fmt.Fprintf(w, `{"name":"London","coord":{"lat":51.5085,"lon":-0.1257},"main":{"temp":%v},"wind":{"speed":%v},"weather":[{"main":"Clouds","description":"%s"}],"sys":{"country":"GB"}}`, temp, wind, description)
+		case "uvi":
+			w.Write([]byte(`{"value":4.5}`))
+		case "air_pollution":
+			w.Write([]byte(`{"list":[{"main":{"aqi":2}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+// Fail makes every request to endpoint answer 500 Internal Server Error
+func (f *fakeUpstream) Fail(endpoint string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failing[endpoint] = true
+}
+
+// Calls returns how many times endpoint was requested
+func (f *fakeUpstream) Calls(endpoint string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[endpoint]
+}
+
+// Query returns the query string of the last request to endpoint
+func (f *fakeUpstream) Query(endpoint string) url.Values {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.queries[endpoint]
+}
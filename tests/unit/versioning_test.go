@@ -0,0 +1,123 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/server"
+	"github.com/Vivek-Prakash1307/weather-Microservices/internal/tenant"
+)
+
+func getWithAccept(t *testing.T, url, accept string) *http.Response {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s error = %v", url, err)
+	}
+	resp.Body.Close()
+	return resp
+}
+
+func TestVersionedRoutes(t *testing.T) {
+	api := newAPIServer(t, nil)
+
+	tests := []struct {
+		name       string
+		path       string
+		accept     string
+		status     int
+		deprecated bool
+	}{
+		{"Versioned", "/v1/weather?city=London", "", http.StatusOK, false},
+		{"Versioned with matching Accept", "/v1/weather?city=London", "application/json; version=1", http.StatusOK, false},
+		{"Versioned with other Accept", "/v1/weather?city=London", "application/vnd.weather.v2+json", http.StatusNotAcceptable, false},
+		{"Legacy alias", "/weather?city=London", "", http.StatusOK, true},
+		{"Legacy alias with path variable", "/cache/london", "", http.StatusOK, true},
+		{"Legacy with unknown Accept version", "/weather?city=London", "application/vnd.weather.v9+json", http.StatusNotAcceptable, false},
+		{"Operational route", "/health", "", http.StatusOK, false},
+		{"Unknown path", "/weather/extra", "", http.StatusNotFound, true},
+	}
+
+	// Populate the cache for the path variable case
+	getWithAccept(t, api.URL+"/v1/weather?city=London", "")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := getWithAccept(t, api.URL+tt.path, tt.accept)
+			if resp.StatusCode != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+			if deprecated := resp.Header.Get("Deprecation") != ""; deprecated != tt.deprecated {
+				t.Errorf("Expected deprecated=%v, got Deprecation %q", tt.deprecated, resp.Header.Get("Deprecation"))
+			}
+		})
+	}
+}
+
+func TestLegacyRoutes_DeprecationHeaders(t *testing.T) {
+	api := newAPIServer(t, nil)
+	resp := getWithAccept(t, api.URL+"/weather?city=London", "")
+
+	if got := resp.Header.Get("Deprecation"); !strings.HasPrefix(got, "@") {
+		t.Errorf("Expected Deprecation date, got %q", got)
+	}
+	if got := resp.Header.Get("Sunset"); !strings.HasSuffix(got, "GMT") {
+		t.Errorf("Expected Sunset HTTP date, got %q", got)
+	}
+	if got := resp.Header.Get("Link"); got != `</v1/weather>; rel="successor-version"` {
+		t.Errorf("Expected successor link, got %q", got)
+	}
+	if got := resp.Header.Get("API-Version"); got != "v1" {
+		t.Errorf("Expected API-Version v1, got %q", got)
+	}
+}
+
+func TestRequestedVersion(t *testing.T) {
+	tests := []struct {
+		accept   string
+		expected string
+	}{
+		{"", ""},
+		{"application/json", ""},
+		{"*/*", ""},
+		{"application/vnd.weather.v2+json", "v2"},
+		{"application/json; version=2", "v2"},
+		{"text/html, application/vnd.weather.v1+json;q=0.9", "v1"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/weather", nil)
+		req.Header.Set("Accept", tt.accept)
+		if got := server.RequestedVersion(req); got != tt.expected {
+			t.Errorf("RequestedVersion(%q) = %q, expected %q", tt.accept, got, tt.expected)
+		}
+	}
+}
+
+func TestTenantAllows_VersionedPaths(t *testing.T) {
+	tn := &tenant.Tenant{ID: "team-a", AllowedEndpoints: []string{"/weather", "/cache/*"}}
+
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{"/weather", true},
+		{"/v1/weather", true},
+		{"/v2/weather", true},
+		{"/v1/cache/london", true},
+		{"/v1/config", false},
+		{"/vx/weather", false},
+	}
+
+	for _, tt := range tests {
+		if got := tn.Allows(tt.path); got != tt.expected {
+			t.Errorf("Allows(%q) = %v, expected %v", tt.path, got, tt.expected)
+		}
+	}
+}
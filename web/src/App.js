@@ -97,7 +97,7 @@ function App() {
     setLoading(true);
     setError('');
     try {
-      const res = await fetch(`${API_BASE_URL}/weather?city=${encodeURIComponent(city)}`);
+      const res = await fetch(`${API_BASE_URL}/v1/weather?city=${encodeURIComponent(city)}`);
       const json = await res.json();
       
       if (!res.ok) {